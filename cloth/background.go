@@ -0,0 +1,117 @@
+package cloth
+
+import (
+	"image"
+	"image/color"
+	"strings"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// The styles of the background.
+const (
+	// backgroundSolid fills the background with a single color.
+	backgroundSolid = iota
+	// backgroundGradient fills the background with a vertical gradient.
+	backgroundGradient
+	// backgroundImage stretches or tiles an image across the background.
+	backgroundImage
+)
+
+// Background is the background drawn behind the cloth.
+type Background struct {
+	Style int
+	// Color is the color of the solid background. The zero color uses the color scheme.
+	Color color.NRGBA
+	// Gradient runs from the top to the bottom of the gradient background. The nil gradient is derived from the color scheme.
+	Gradient Gradient
+	// Image is the image of the image background, which is stretched over the whole area, or tiled if Tile is set.
+	Image *paint.ImageOp
+	Tile  bool
+}
+
+// ParseBackground parses the background: "solid" or "gradient" for the colors of the color scheme,
+// a hex color for a solid background, a comma separated list of hex colors for a gradient,
+// or otherwise the path of a PNG or JPEG image.
+func ParseBackground(s string) (Background, error) {
+	switch {
+	case s == "solid":
+		return Background{Style: backgroundSolid}, nil
+	case s == "gradient":
+		return Background{Style: backgroundGradient}, nil
+	case strings.HasPrefix(s, "#") && !strings.Contains(s, ","):
+		col, err := parseColor(s)
+		return Background{Style: backgroundSolid, Color: col}, err
+	case strings.HasPrefix(s, "#"):
+		g, err := ParseGradient(s)
+		return Background{Style: backgroundGradient, Gradient: g}, err
+	}
+	img, err := LoadTexture(s)
+	if err != nil {
+		return Background{}, err
+	}
+	imageOp := paint.NewImageOp(img)
+	return Background{Style: backgroundImage, Image: &imageOp}, nil
+}
+
+// SetBackground sets the background drawn behind the cloth.
+func (w *ClothWidget) SetBackground(b Background) {
+	w.background = b
+}
+
+// cycleBackground switches to the next background style. The image style is skipped without an image.
+func (w *ClothWidget) cycleBackground() {
+	w.background.Style = (w.background.Style + 1) % (backgroundImage + 1)
+	if w.background.Style == backgroundImage && w.background.Image == nil {
+		w.background.Style = backgroundSolid
+	}
+}
+
+// drawBackground fills the widget area with the background.
+func (w *ClothWidget) drawBackground(gtx layout.Context) {
+	b := w.background
+	switch {
+	case b.Style == backgroundGradient:
+		g := b.Gradient
+		if g == nil {
+			g = Gradient{w.scheme.Background, w.scheme.Water}
+		}
+		g.paint(gtx, image.Rectangle{Max: w.size}, false)
+	case b.Style == backgroundImage && b.Image != nil:
+		defer clip.Rect{Max: w.size}.Push(gtx.Ops).Pop()
+		size := b.Image.Size()
+		if size.X == 0 || size.Y == 0 {
+			return
+		}
+		b.Image.Add(gtx.Ops)
+		if !b.Tile {
+			scale := f32.Pt(float32(w.size.X)/float32(size.X), float32(w.size.Y)/float32(size.Y))
+			defer op.Affine(f32.Affine2D{}.Scale(f32.Point{}, scale)).Push(gtx.Ops).Pop()
+			paint.PaintOp{}.Add(gtx.Ops)
+			return
+		}
+		for y := 0; y < w.size.Y; y += size.Y {
+			for x := 0; x < w.size.X; x += size.X {
+				tile := op.Offset(image.Pt(x, y)).Push(gtx.Ops)
+				paint.PaintOp{}.Add(gtx.Ops)
+				tile.Pop()
+			}
+		}
+	case b.Color.A > 0:
+		fillBackground(gtx, b.Color)
+	case w.cloth.Underwater():
+		fillBackground(gtx, w.scheme.Water)
+	default:
+		fillBackground(gtx, w.scheme.Background)
+	}
+}
+
+// fillBackground fills the whole area with the color.
+func fillBackground(gtx layout.Context, col color.NRGBA) {
+	paint.ColorOp{Color: col}.Add(gtx.Ops)
+	paint.PaintOp{}.Add(gtx.Ops)
+}
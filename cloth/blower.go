@@ -0,0 +1,31 @@
+package cloth
+
+import "math"
+
+const (
+	// blowerRange is the distance the air of the blower reaches.
+	blowerRange = 300
+	// blowerAngle is the half angle of the blower cone in radians.
+	blowerAngle = math.Pi / 8
+	// blowerStrength is the acceleration of the particles right in front of the blower.
+	blowerStrength = 4000
+)
+
+// blowerAt returns the acceleration of the air blown from the cursor acting on a particle at the {x, y} position.
+// The air is blown in a cone pointing in the direction the cursor is moving, fading out with the distance.
+func blowerAt(mouse *Mouse, x, y float64) (ax, ay float64) {
+	if !mouse.blowing {
+		return 0, 0
+	}
+	dx, dy := x-mouse.x, y-mouse.y
+	dist := distance(dx, dy)
+	if dist >= blowerRange || dist == 0 {
+		return 0, 0
+	}
+	nx, ny := dx/dist, dy/dist
+	if fround(nx*mouse.dirX)+fround(ny*mouse.dirY) < cos(blowerAngle) {
+		return 0, 0
+	}
+	a := fround(blowerStrength * (1 - dist/blowerRange))
+	return fround(nx * a), fround(ny * a)
+}
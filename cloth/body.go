@@ -0,0 +1,162 @@
+package cloth
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+const (
+	// bodyRadius is the radius of the bouncing ball.
+	bodyRadius = 30
+	// bodyMass is the mass of the bouncing ball relative to the mass of the cloth particles.
+	bodyMass = 40
+)
+
+// The parameters of the projectiles thrown at the cloth.
+const (
+	projectileRadius = 12
+	projectileMass   = 20
+	// projectileSpeed is the speed of the thrown projectile in pixels per step,
+	// relative to the length of the throwing gesture.
+	projectileSpeed = 0.15
+	// pierceSpeed is the speed in pixels per step over which a projectile tears through the cloth.
+	pierceSpeed = 12
+	// maxProjectiles is the number of the projectiles in flight, over which the oldest one is thrown again.
+	maxProjectiles = 8
+)
+
+// bodyColor is the fill color of the bouncing ball.
+var bodyColor = color.NRGBA{R: 0x39, G: 0x8d, B: 0xd9, A: 0xff}
+
+// Body is a heavy ball moving together with the cloth. Its center is a particle of the cloth,
+// so it's integrated, saved into the snapshots and replayed the same way as the cloth itself.
+// The ball and the cloth are pushing each other apart proportionally to their inverse masses.
+type Body struct {
+	index  int
+	radius float64
+	// piercing is set for the projectiles, which are tearing through the cloth when they are fast enough.
+	piercing bool
+	// still is the number of steps the projectile has been at rest.
+	still int
+}
+
+// addBody adds a ball with the radius `r` and the mass `mass` centered at the {x, y} position.
+func (c *Cloth) addBody(x, y, r, mass float64) *Body {
+	p := c.addParticle(x, y, -1, -1)
+	p.mass = mass
+	b := &Body{index: len(c.particles) - 1, radius: r}
+	c.bodies = append(c.bodies, b)
+	return b
+}
+
+// Throw launches a heavy projectile from the {x, y} position with the {vx, vy} velocity in pixels per step.
+// The particles of the retired projectiles are reused, and over maxProjectiles the oldest one is thrown again,
+// so the throws are not adding up to the particles the projectiles are colliding with.
+func (c *Cloth) Throw(x, y, vx, vy float64) {
+	var b *Body
+	var thrown, oldest int
+	for i := len(c.bodies) - 1; i >= 0; i-- {
+		if !c.bodies[i].piercing {
+			continue
+		}
+		thrown++
+		oldest = i
+		if !c.particles[c.bodies[i].index].isActive {
+			b = c.bodies[i]
+			break
+		}
+	}
+	if b == nil && thrown >= maxProjectiles {
+		b = c.bodies[oldest]
+	}
+	if b == nil {
+		b = c.addBody(x, y, projectileRadius, projectileMass)
+		b.piercing = true
+	} else {
+		// The reused projectile is moved to the end, so the bodies are kept in the order they were thrown.
+		for i, o := range c.bodies {
+			if o == b {
+				c.bodies = append(append(c.bodies[:i:i], c.bodies[i+1:]...), b)
+				break
+			}
+		}
+		p := NewParticle(x, y, c.color)
+		p.col, p.row, p.mass = -1, -1, projectileMass
+		c.particles[b.index] = p
+		b.still = 0
+	}
+	p := c.particles[b.index]
+	p.px, p.py = x-vx, y-vy
+}
+
+// retireProjectiles removes the projectiles which have left the `width` and `height` area
+// or have come to rest, so they are no longer colliding with the cloth.
+func (c *Cloth) retireProjectiles(width, height int) {
+	for _, b := range c.bodies {
+		p := c.particles[b.index]
+		if !b.piercing || !p.isActive {
+			continue
+		}
+		if math.Max(math.Abs(p.x-p.px), math.Abs(p.y-p.py)) < sleepMotion {
+			b.still++
+		} else {
+			b.still = 0
+		}
+		out := p.x < -b.radius || p.x > float64(width)+b.radius || p.y > float64(height)+b.radius
+		if out || b.still >= sleepSteps {
+			p.isActive = false
+		}
+	}
+}
+
+// collide resolves the collisions between the ball and the cloth particles.
+func (b *Body) collide(c *Cloth) {
+	ball := c.particles[b.index]
+	if !ball.isActive {
+		return
+	}
+	pierce := b.piercing && distance(ball.x-ball.px, ball.y-ball.py) > pierceSpeed
+	for _, p := range c.particles {
+		if p == ball || !p.isActive {
+			continue
+		}
+		dx, dy := p.x-ball.x, p.y-ball.y
+		dist := distance(dx, dy)
+		if dist >= b.radius {
+			continue
+		}
+		// The fast projectile is tearing a hole in the cloth instead of pushing it.
+		if pierce && p.col >= 0 {
+			c.applyEdit(&tearEdit{p: p})
+			continue
+		}
+		w1, w2 := p.invMass(), ball.invMass()
+		if w1+w2 == 0 {
+			continue
+		}
+		if dist == 0 {
+			dx, dy, dist = 0, -1, 1
+		}
+		// The penetration is split between the particle and the ball by their inverse masses.
+		depth := fround((b.radius - dist) / dist / (w1 + w2))
+		p.x += fround(dx * depth * w1)
+		p.y += fround(dy * depth * w1)
+		ball.x -= fround(dx * depth * w2)
+		ball.y -= fround(dy * depth * w2)
+	}
+}
+
+// draw draws the ball at its interpolated position.
+func (b *Body) draw(gtx layout.Context, c *Cloth, alpha float64) {
+	if !c.particles[b.index].isActive {
+		return
+	}
+	x, y := c.particles[b.index].position(alpha)
+	rect := image.Rect(int(x-b.radius), int(y-b.radius), int(x+b.radius), int(y+b.radius))
+	paint.FillShape(gtx.Ops, bodyColor, clip.Ellipse(rect).Op(gtx.Ops))
+}
@@ -0,0 +1,116 @@
+package cloth
+
+import (
+	"math"
+	"math/rand"
+
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+const (
+	// burstSparks is the number of the sparks bursting out of a torn stick.
+	burstSparks = 5
+	// minSparkSpeed and maxSparkSpeed are limiting the initial speed of the sparks in pixels per second.
+	minSparkSpeed = 40
+	maxSparkSpeed = 180
+	// minSparkLife and maxSparkLife are limiting the time in seconds the sparks are fading out for.
+	minSparkLife = 0.3
+	maxSparkLife = 0.8
+	// sparkRadius is the radius of a spark.
+	sparkRadius = 1.2
+	// sparkShades is the number of the opacity levels of the fading sparks.
+	// The sparks of the same opacity are drawn as a single clip path.
+	sparkShades = 8
+	// maxSparks limits the number of the live sparks. The new sparks are dropped over the limit.
+	maxSparks = 2000
+)
+
+// spark is a short-lived effect particle.
+type spark struct {
+	x, y   float64
+	vx, vy float64
+	age    float64
+	life   float64
+}
+
+// Burst is a lightweight effect particle system of the sparks bursting out of the tears. The sparks are
+// only moving by their velocity and the gravity and they are not interacting with the cloth.
+// They are using their own random generator, so they don't change the random sequence of the simulation.
+type Burst struct {
+	sparks []spark
+	rng    *rand.Rand
+}
+
+// NewBurst creates an empty spark burst system.
+func NewBurst() *Burst {
+	return &Burst{rng: rand.New(rand.NewSource(1))}
+}
+
+// SetBursts turns on or off bursting out the sparks from the tears.
+func (c *Cloth) SetBursts(on bool) {
+	c.burst = nil
+	if on {
+		c.burst = NewBurst()
+	}
+}
+
+// Emit bursts the sparks out of the {x, y} position in random directions.
+func (b *Burst) Emit(x, y float64) {
+	for i := 0; i < burstSparks && len(b.sparks) < maxSparks; i++ {
+		angle := b.rng.Float64() * 2 * math.Pi
+		speed := minSparkSpeed + b.rng.Float64()*(maxSparkSpeed-minSparkSpeed)
+		b.sparks = append(b.sparks, spark{
+			x: x, y: y,
+			vx:   math.Cos(angle) * speed,
+			vy:   math.Sin(angle) * speed,
+			life: minSparkLife + b.rng.Float64()*(maxSparkLife-minSparkLife),
+		})
+	}
+}
+
+// Update moves the sparks by the time step under the gravity and removes the faded out ones.
+func (b *Burst) Update(gx, gy, delta float64) {
+	sparks := b.sparks[:0]
+	for _, s := range b.sparks {
+		if s.age += delta; s.age >= s.life {
+			continue
+		}
+		s.vx += gx * delta
+		s.vy += gy * delta
+		s.x += s.vx * delta
+		s.y += s.vy * delta
+		sparks = append(sparks, s)
+	}
+	b.sparks = sparks
+}
+
+// Clear removes all the sparks.
+func (b *Burst) Clear() {
+	b.sparks = b.sparks[:0]
+}
+
+// Draw draws the sparks fading out with their age.
+func (b *Burst) Draw(gtx layout.Context, c *Cloth) {
+	var shades [sparkShades]clip.Path
+	var used [sparkShades]bool
+	for _, s := range b.sparks {
+		shade := int((1 - s.age/s.life) * sparkShades)
+		if shade >= sparkShades {
+			shade = sparkShades - 1
+		}
+		if !used[shade] {
+			shades[shade].Begin(gtx.Ops)
+			used[shade] = true
+		}
+		addDot(&shades[shade], float32(s.x), float32(s.y), sparkRadius)
+	}
+	for shade := range shades {
+		if used[shade] {
+			col := c.color
+			col.A = uint8(float64(col.A) * float64(shade+1) / sparkShades)
+			paint.FillShape(gtx.Ops, col, clip.Outline{Path: shades[shade].End()}.Op())
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package cloth
+
+import (
+	"math"
+
+	"gioui.org/f32"
+	"gioui.org/io/pointer"
+)
+
+const (
+	// minZoom and maxZoom are limiting the zoom of the camera.
+	minZoom = 0.25
+	maxZoom = 4
+	// zoomRate is the relative zoom change per scrolled pixel.
+	zoomRate = 0.01
+)
+
+// Camera maps the world coordinates of the simulation to the screen coordinates of the widget.
+// The world is measured in Dp, so it's scaled by the screen density and the zoom around its origin
+// and then shifted by the offset.
+// The pointer positions are mapped back to the world, so the tools are acting on the cloth under the pointer.
+type Camera struct {
+	zoom    float32
+	offset  f32.Point // the screen position of the world origin
+	density float32   // the pixels per world unit (Dp) of the screen
+}
+
+// newCamera creates a camera showing the world unscaled.
+func newCamera() *Camera {
+	return &Camera{zoom: 1, density: 1}
+}
+
+// scale returns the pixels per world unit, including the zoom.
+func (c *Camera) scale() float32 {
+	return c.zoom * c.density
+}
+
+// transform returns the transformation of the world coordinates to the screen coordinates.
+func (c *Camera) transform() f32.Affine2D {
+	return f32.Affine2D{}.Scale(f32.Point{}, f32.Pt(c.scale(), c.scale())).Offset(c.offset)
+}
+
+// toWorld maps the screen position to the world.
+func (c *Camera) toWorld(p f32.Point) f32.Point {
+	return c.transform().Invert().Transform(p)
+}
+
+// zoomAt scales the view by `factor` around the `screen` position, which stays in place.
+func (c *Camera) zoomAt(screen f32.Point, factor float32) {
+	world := c.toWorld(screen)
+	c.zoom = float32(math.Max(minZoom, math.Min(float64(c.zoom*factor), maxZoom)))
+	c.offset = screen.Sub(world.Mul(c.scale()))
+}
+
+// pan shifts the view by the `delta` screen distance.
+func (c *Camera) pan(delta f32.Point) {
+	c.offset = c.offset.Add(delta)
+}
+
+// handlePan pans the camera by dragging with the middle button.
+// It reports whether the event belongs to the panning.
+func (w *ClothWidget) handlePan(ev pointer.Event) bool {
+	switch {
+	case ev.Type == pointer.Press && ev.Buttons == pointer.ButtonTertiary:
+		w.panning, w.panAt = true, ev.Position
+	case !w.panning:
+		return false
+	case ev.Type == pointer.Drag:
+		w.camera.pan(ev.Position.Sub(w.panAt))
+		w.panAt = ev.Position
+	case ev.Type == pointer.Release, ev.Type == pointer.Cancel:
+		w.panning = false
+	}
+	return true
+}
+
+// handlePinch zooms and pans the camera with two fingers, when the first one has been pressed away from the cloth.
+// Otherwise the second finger is dragging the cloth. It reports whether the event belongs to the pinch.
+func (w *ClothWidget) handlePinch(ev pointer.Event) bool {
+	if ev.Source != pointer.Touch {
+		return false
+	}
+	switch {
+	case ev.Type == pointer.Press && !w.pointing:
+		pos := w.camera.toWorld(ev.Position)
+		w.pinchA = ev.Position
+		w.offCloth = w.cloth.nearest(float64(pos.X), float64(pos.Y), w.mouse.getFocusArea()) < 0
+	case ev.Type == pointer.Press && w.offCloth && !w.pinching && len(w.mouse.touches) == 0:
+		w.pinching, w.pinchID, w.pinchB = true, ev.PointerID, ev.Position
+		// The first finger stops dragging the cloth.
+		w.mouse.releaseLeftButton()
+		w.mouse.setDragging(false)
+		w.isDragging = false
+		return true
+	case w.pinching && (ev.PointerID == w.primary || ev.PointerID == w.pinchID):
+		switch ev.Type {
+		case pointer.Drag:
+			a, b := w.pinchA, w.pinchB
+			if ev.PointerID == w.primary {
+				w.pinchA = ev.Position
+			} else {
+				w.pinchB = ev.Position
+			}
+			// The view follows the midpoint of the fingers and it's scaled by the change of their distance.
+			mid := w.pinchA.Add(w.pinchB).Mul(0.5)
+			w.camera.pan(mid.Sub(a.Add(b).Mul(0.5)))
+			if d := distance(float64(a.X-b.X), float64(a.Y-b.Y)); d > 0 {
+				w.camera.zoomAt(mid, float32(distance(float64(w.pinchA.X-w.pinchB.X), float64(w.pinchA.Y-w.pinchB.Y))/d))
+			}
+		case pointer.Release, pointer.Cancel:
+			w.pinching = false
+			if ev.PointerID == w.primary || ev.Type == pointer.Cancel {
+				w.pointing = false
+			}
+		}
+		return true
+	case ev.PointerID == w.primary:
+		w.pinchA = ev.Position
+	}
+	return false
+}
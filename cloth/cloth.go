@@ -0,0 +1,773 @@
+// Package cloth implements a tearable cloth simulation, which can be embedded
+// into any Gio user interface as a widget.
+package cloth
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// Forces holds the external accelerations acting on every cloth particle.
+type Forces struct {
+	GravityX, GravityY float64
+	WindX, WindY       float64
+}
+
+// Grain holds the material properties of the fabric along one of its axes.
+type Grain struct {
+	// Stiffness scales the stiffness of the sticks, where 1 is the default stiffness.
+	Stiffness float64
+	// Tear scales the tear threshold of the sticks.
+	Tear float64
+}
+
+// Surface holds the collision response of a boundary.
+type Surface struct {
+	// Friction is the fraction of the tangential velocity lost in the collision.
+	Friction float64
+	// Restitution is the fraction of the normal velocity kept by bouncing back.
+	Restitution float64
+}
+
+// settings holds the parameters of the simulation step which can be changed while the cloth
+// is running. They are recorded for every step, so the timeline can replay them.
+type settings struct {
+	forces      Forces
+	windModel   WindModel
+	iterations  int
+	solver      int
+	model       int
+	springK     float64
+	springDamp  float64
+	friction    float64
+	strainLimit float64
+	dragX       float64
+	dragY       float64
+	tearDist    float64
+	plastic     bool
+	warp        Grain
+	weft        Grain
+	floor       Surface
+	walls       bool
+	underwater  bool
+	fieldRadius float64
+	fieldCharge float64
+	selfCollide bool
+	obstacles   []Obstacle
+	ball        Circle
+	ballOn      bool
+}
+
+type Cloth struct {
+	settings
+
+	width   int
+	height  int
+	spacing int
+	color   color.NRGBA
+	jitter  float64
+	seed    int64
+	// compliance is the XPBD compliance of the sticks created by Init.
+	compliance float64
+	// shear and bend enable the stiffening sticks of the cloth created by Init.
+	shear, bend bool
+	// sliding replaces the pins created by Init with pins sliding horizontally.
+	sliding bool
+	// falloff and falloffAmount are weakening the sticks created by Init.
+	falloff       int
+	falloffAmount float64
+	// mass returns the mass of the particle at the {col, row} grid position when the cloth is created.
+	mass func(col, row int) float64
+
+	simTime float64
+	noise   *Noise
+	rng     *rand.Rand
+	grid    map[image.Point][]int
+	preset  *Preset
+	balloon *Balloon
+	bodies  []*Body
+	// fill turns on filling the cells of the cloth instead of drawing its sticks.
+	fill bool
+	// texture is the image mapped across the cloth grid, which is gridCols by gridRows cells as it has been built.
+	texture            *paint.ImageOp
+	gridCols, gridRows int
+	// depth turns on the 3D simulation, and yaw is the angle of the camera orbiting around the 3D cloth.
+	depth bool
+	yaw   float64
+	// fraying turns on leaving the dangling threads at the ends of the torn sticks.
+	fraying bool
+	frays   []*thread
+	// burst holds the sparks bursting out of the tears. It's nil if the bursts are off.
+	burst *Burst
+	// shadow turns on drawing the shadow cast by the cloth onto the floor.
+	shadow bool
+	// trails turns on drawing the motion trails, and trail holds the particle positions of the last frames.
+	trails bool
+	trail  [][]f32.Point
+	// dots turns on drawing a dot at every particle.
+	dots bool
+	// render is the mode coloring the sticks.
+	render int
+	// gradient colors the sticks by their strain in the strain render mode.
+	gradient Gradient
+	// minWidth and maxWidth are the line widths of the taut and the slack sticks, if the tension width is on.
+	minWidth, maxWidth float64
+	// strokeWidth is the width of the stroked sticks, and lineCap is the cap style of their ends.
+	strokeWidth float64
+	lineCap     int
+	// sheets is the number of the separate sheets of cloth, which are colliding with each other.
+	sheets int
+	// diagnose turns on the collection of the step diagnostics into stats.
+	diagnose bool
+	stats    Stats
+	// sleeping enables putting the resting particles to sleep.
+	sleeping bool
+	sleepKey sleepKey
+
+	particles   []*Particle
+	constraints []*Constraint
+	history     *History
+	motion      float64
+	maxTension  float64
+
+	isInitialized bool
+	// origin is the top-left position the cloth has been built at, moved together with the cloth by Translate.
+	origin image.Point
+}
+
+// NewCloth creates a new cloth which dimension is calculated based on
+// the application window width and height and the spacing between the sticks.
+func NewCloth(width, height, spacing int, friction float64, col color.NRGBA) *Cloth {
+	return &Cloth{
+		width:    width,
+		height:   height,
+		spacing:  spacing,
+		color:    col,
+		history:  NewHistory(defUndoDepth),
+		gradient: strainGradient,
+		settings: settings{
+			forces:     Forces{GravityY: gravityForce},
+			dragX:      1 - friction,
+			dragY:      1 - friction,
+			tearDist:   stickTearDist,
+			warp:       Grain{Stiffness: 1, Tear: 1},
+			weft:       Grain{Stiffness: 1, Tear: 1},
+			walls:      true,
+			iterations: 1,
+		},
+	}
+}
+
+// Init initializes the cloth where the `posX` and `posY`
+// is the {x, y} position of the cloth's the top-left side.
+// The shape of the cloth is built by the preset, which defaults to a rectangular grid.
+func (c *Cloth) Init(posX, posY int) {
+	// The random generator is seeded on every initialization, so the jitter is reproducible.
+	c.rng = rand.New(rand.NewSource(c.seed))
+	c.noise = NewNoise(c.seed)
+	c.balloon, c.bodies, c.frays = nil, nil, nil
+	if c.burst != nil {
+		c.burst.Clear()
+	}
+	c.sheets = 1
+	c.origin = image.Pt(posX, posY)
+
+	if c.preset != nil && c.preset.build != nil {
+		c.preset.build(c, posX, posY)
+	} else {
+		c.initGrid(posX, posY)
+	}
+	c.gridCols, c.gridRows = 0, 0
+	for _, p := range c.particles {
+		if p.col > c.gridCols {
+			c.gridCols = p.col
+		}
+		if p.row > c.gridRows {
+			c.gridRows = p.row
+		}
+	}
+	c.isInitialized = true
+}
+
+// initGrid builds the cloth as a grid of particles connected with sticks,
+// pinned up at a few points of the top row.
+func (c *Cloth) initGrid(posX, posY int) {
+	clothX := c.width / c.spacing
+	clothY := c.height / c.spacing
+	spacing := float64(c.spacing)
+	// The grid might be added next to the particles of another sheet.
+	base := len(c.particles)
+	at := func(x, y int) *Particle {
+		return c.particles[base+x+y*(clothX+1)]
+	}
+
+	for y := 0; y <= clothY; y++ {
+		for x := 0; x <= clothX; x++ {
+			px := posX + x*c.spacing
+			py := posY + y*c.spacing
+			particle := c.addParticle(float64(px), float64(py), x, y)
+
+			// Connect the particles with sticks but skip the particles from the first column and row.
+			// We connect the particles from the second row and column onward to the particles before.
+			if y != 0 {
+				c.connect(at(x, y-1), particle, spacing, stickStructural)
+			}
+			if x != 0 {
+				c.connect(at(x-1, y), particle, spacing, stickStructural)
+			}
+			// The diagonal shear and the second neighbour bending sticks are making the fabric stiffer.
+			if c.shear && y != 0 {
+				if x != 0 {
+					c.connect(at(x-1, y-1), particle, spacing*math.Sqrt2, stickShear)
+				}
+				if x != clothX {
+					c.connect(at(x+1, y-1), particle, spacing*math.Sqrt2, stickShear)
+				}
+			}
+			if c.bend {
+				if y > 1 {
+					c.connect(at(x, y-2), particle, 2*spacing, stickBend)
+				}
+				if x > 1 {
+					c.connect(at(x-2, y), particle, 2*spacing, stickBend)
+				}
+			}
+
+			// The top row is pinned at every seventh of its width, or at every particle of a cloth narrower than that.
+			pinX := x % maxInt(1, clothX/7)
+			if y == 0 && pinX == 0 {
+				// The sliding pins are holding the cloth only vertically, like the rings of a curtain on a rod.
+				if c.sliding {
+					particle.rail, particle.railY = true, particle.y
+				} else {
+					particle.pinX = true
+				}
+			}
+		}
+	}
+}
+
+// addParticle adds a new particle at the {x, y} position,
+// where {col, row} is the position of the particle in the cloth grid.
+func (c *Cloth) addParticle(x, y float64, col, row int) *Particle {
+	// A tiny random displacement breaks the symmetry of the cloth, which otherwise
+	// might get stuck in an unstable symmetric configuration draping over an obstacle.
+	offset := c.jitter * float64(c.spacing)
+	jx := fround(offset * (2*c.rng.Float64() - 1))
+	jy := fround(offset * (2*c.rng.Float64() - 1))
+
+	particle := NewParticle(x+jx, y+jy, c.color)
+	particle.col, particle.row = col, row
+	if c.mass != nil {
+		particle.mass = math.Max(c.mass(col, row), minMass)
+	}
+	c.particles = append(c.particles, particle)
+	return particle
+}
+
+// connect connects two particles with a new stick of the given kind.
+func (c *Cloth) connect(p1, p2 *Particle, length float64, kind int) {
+	constraint := NewConstraint(p1, p2, length, c.color)
+	constraint.compliance = c.compliance
+	constraint.kind = kind
+	constraint.stiffness = c.stickStiffness(p1, p2)
+	c.constraints = append(c.constraints, constraint)
+}
+
+// Update is invoked on each frame event of the Gio internal window calls.
+// It advances the simulation with one step and draws the cloth.
+func (cloth *Cloth) Update(gtx layout.Context, mouse *Mouse, delta float64) {
+	cloth.Step(mouse, gtx.Constraints.Max.X, gtx.Constraints.Max.Y, delta)
+	cloth.Draw(gtx, mouse, 1)
+}
+
+// Step updates the cloth particles, which are the basic entities over the
+// cloth constraints are applied and solved using Verlet integration.
+// The `width` and `height` are the dimensions of the area the cloth is moving in.
+func (cloth *Cloth) Step(mouse *Mouse, width, height int, delta float64) {
+	cloth.moveHanger(mouse)
+	cloth.balloon.inflate(cloth)
+	if cloth.model == modelSpring {
+		for _, c := range cloth.constraints {
+			if c.p1.isActive && !(c.p1.resting() && c.p2.resting()) {
+				c.spring(cloth, mouse, delta)
+			}
+		}
+	}
+	for _, p := range cloth.particles {
+		p.Update(cloth, mouse, width, height, delta)
+	}
+	cloth.simTime += delta
+	cloth.updateFrays(delta)
+	if cloth.burst != nil {
+		cloth.burst.Update(cloth.forces.GravityX, cloth.forces.GravityY, delta)
+	}
+
+	// The XPBD multipliers are accumulated over the iterations of a single step.
+	if cloth.solver == solverXPBD {
+		for _, c := range cloth.constraints {
+			c.lambda = 0
+		}
+	}
+	for i := 0; i < cloth.iterations; i++ {
+		for _, c := range cloth.constraints {
+			// The sticks between the resting particles are already satisfied.
+			if c.p1.isActive && !(c.p1.resting() && c.p2.resting()) && cloth.model != modelSpring {
+				c.Update(cloth, mouse, delta)
+			}
+		}
+		cloth.collideObstacles()
+		for _, b := range cloth.bodies {
+			b.collide(cloth)
+		}
+		cloth.holdRails()
+		cloth.pullNeedle(mouse)
+	}
+
+	cloth.retireProjectiles(width, height)
+	if cloth.strainLimit > 0 {
+		cloth.limitStrain()
+	}
+	if cloth.selfCollide || cloth.sheets > 1 {
+		cloth.collide()
+	}
+	if cloth.sleeping {
+		cloth.settle()
+	}
+
+	cloth.maxTension = 0
+	for _, c := range cloth.constraints {
+		if c.p1.isActive {
+			cloth.maxTension = math.Max(cloth.maxTension, c.strain)
+		}
+	}
+
+	cloth.motion = 0
+	for _, p := range cloth.particles {
+		// The particles stopped by a wall or the floor are bouncing in place, so they aren't moving the cloth.
+		if p.isActive && !p.pinX && !p.clamped {
+			cloth.motion = math.Max(cloth.motion, math.Max(math.Abs(p.x-p.px), math.Max(math.Abs(p.y-p.py), math.Abs(p.z-p.pz))))
+		}
+	}
+	if cloth.diagnose {
+		cloth.measure(height, delta)
+	}
+	if mouse.cutting {
+		cloth.cut(mouse.sx, mouse.sy, mouse.x, mouse.y)
+	}
+	if mouse.stitching {
+		cloth.repair(mouse.x, mouse.y, mouse.getFocusArea())
+	}
+	mouse.endSweep()
+}
+
+// keepPositions stores the current particle positions as the previous step positions,
+// which the drawing interpolates from. It should be called before each full physics step.
+func (cloth *Cloth) keepPositions() {
+	for _, p := range cloth.particles {
+		p.lx, p.ly, p.lz = p.x, p.y, p.z
+	}
+}
+
+// MaxTension returns the length of the most stretched stick in the last simulation step,
+// as a fraction of the distance over which the dragged sticks are torn.
+func (cloth *Cloth) MaxTension() float64 {
+	return cloth.maxTension
+}
+
+// flashStrained lights up the sticks stretched over the `threshold` fraction
+// of the tear distance while the cloth is being pulled, while the flash of the other sticks fades out.
+func (cloth *Cloth) flashStrained(threshold float64, pulled bool) {
+	for _, c := range cloth.constraints {
+		if pulled && c.strain >= threshold {
+			c.flash = 1
+		} else {
+			c.flash *= flashDecay
+		}
+	}
+}
+
+// SetSelfCollision turns the collision between the layers of the folded cloth on or off.
+func (cloth *Cloth) SetSelfCollision(on bool) {
+	cloth.selfCollide = on
+}
+
+// SelfCollision reports whether the collision between the cloth layers is enabled.
+func (cloth *Cloth) SelfCollision() bool {
+	return cloth.selfCollide
+}
+
+// SetStiffness enables the diagonal shear sticks and the second neighbour bending sticks,
+// which are simulating stiffer materials like canvas or paper. They are not drawn.
+// It's used when the cloth is created, so it should be set before Init.
+func (cloth *Cloth) SetStiffness(shear, bend bool) {
+	cloth.shear, cloth.bend = shear, bend
+}
+
+// SetMassFunc sets the function returning the mass of the particles at their {col, row} grid position,
+// e.g. for a weighted hem. It's used when the cloth is created, so it should be set before Init.
+func (cloth *Cloth) SetMassFunc(mass func(col, row int) float64) {
+	cloth.mass = mass
+}
+
+// SetParticleMass changes the mass of the particle at the {col, row} grid position, e.g. for attaching a weight.
+func (cloth *Cloth) SetParticleMass(col, row int, mass float64) {
+	for _, p := range cloth.particles {
+		if p.col == col && p.row == row {
+			p.mass = math.Max(mass, minMass)
+		}
+	}
+}
+
+// Rows returns the number of particle rows of the cloth.
+func (cloth *Cloth) Rows() int {
+	return cloth.height/cloth.spacing + 1
+}
+
+// SetTearThreshold sets the stick length over which the dragged cloth tears. The threshold is clamped
+// to the minimum tear distance, and the values over the maximum tear distance are disabling the tearing.
+func (cloth *Cloth) SetTearThreshold(d float64) {
+	switch {
+	case d > MaxTearDist:
+		cloth.tearDist = math.Inf(1)
+	case d < MinTearDist:
+		cloth.tearDist = MinTearDist
+	default:
+		cloth.tearDist = d
+	}
+}
+
+// TearThreshold returns the stick length over which the dragged cloth tears. It's infinite if the cloth can't be torn.
+func (cloth *Cloth) TearThreshold() float64 {
+	return cloth.tearDist
+}
+
+// SetPlastic turns the plastic deformation on or off. The plastic sticks stretched over
+// their yield length are getting permanently longer instead of snapping back, like a knitwear,
+// and they are torn at a higher limit.
+func (cloth *Cloth) SetPlastic(on bool) {
+	cloth.plastic = on
+}
+
+// Plastic reports whether the plastic deformation is enabled.
+func (cloth *Cloth) Plastic() bool {
+	return cloth.plastic
+}
+
+// SetGrain sets the material properties along the vertical (warp) and the horizontal (weft)
+// axes of the fabric. The stiffness is clamped between the minimum and the maximum grain stiffness.
+func (cloth *Cloth) SetGrain(warp, weft Grain) {
+	clamp := func(g Grain) Grain {
+		g.Stiffness = math.Max(minGrainStiffness, math.Min(g.Stiffness, maxGrainStiffness))
+		g.Tear = math.Max(g.Tear, minGrainTear)
+		return g
+	}
+	cloth.warp, cloth.weft = clamp(warp), clamp(weft)
+}
+
+// SetStrainLimit sets the largest stretch of the sticks relative to their rest length, e.g. 1.1 for 110%,
+// which is enforced after the solver iterations. Zero turns off the strain limiting.
+func (cloth *Cloth) SetStrainLimit(limit float64) {
+	if limit > 0 {
+		limit = math.Max(limit, 1)
+	}
+	cloth.strainLimit = limit
+}
+
+// limitStrain shortens the sticks stretched over the strain limit, which removes the rubbery
+// overstretching of the cloth yanked hard, when the solver iterations can't keep up.
+func (cloth *Cloth) limitStrain() {
+	for _, c := range cloth.constraints {
+		if !c.p1.isActive || c.kind != stickStructural {
+			continue
+		}
+		dx, dy := c.p1.x-c.p2.x, c.p1.y-c.p2.y
+		dist := distance(dx, dy)
+		maxLen := fround(c.length * cloth.strainLimit)
+		w1, w2 := c.p1.invMass(), c.p2.invMass()
+		if dist <= maxLen || w1+w2 == 0 {
+			continue
+		}
+		mul := fround((dist - maxLen) / dist / (w1 + w2))
+		c.p1.x -= fround(dx * mul * w1)
+		c.p1.y -= fround(dy * mul * w1)
+		c.p2.x += fround(dx * mul * w2)
+		c.p2.y += fround(dy * mul * w2)
+	}
+}
+
+// SetFriction sets the fraction of the tangential velocity lost by the particles sliding
+// over the obstacles and by the sliding pins. It's clamped between 0 and 1.
+func (cloth *Cloth) SetFriction(friction float64) {
+	cloth.friction = math.Max(0, math.Min(friction, 1))
+}
+
+// SetFloor sets the collision response of the floor at the bottom of the window.
+// Both the friction and the restitution are clamped between 0 and 1.
+func (cloth *Cloth) SetFloor(floor Surface) {
+	cloth.floor = Surface{
+		Friction:    math.Max(0, math.Min(floor.Friction, 1)),
+		Restitution: math.Max(0, math.Min(floor.Restitution, 1)),
+	}
+}
+
+// SetWalls turns the collision with the left, right and top window edges on or off.
+// The walls are using the same collision response as the floor.
+func (cloth *Cloth) SetWalls(on bool) {
+	cloth.walls = on
+}
+
+// SetDrag sets the air drag slowing down the horizontal and the vertical motion of the particles.
+// It's the fraction of the velocity lost in every step, clamped between zero and the maximum drag.
+func (cloth *Cloth) SetDrag(x, y float64) {
+	cloth.dragX = math.Max(0, math.Min(x, maxDrag))
+	cloth.dragY = math.Max(0, math.Min(y, maxDrag))
+}
+
+// Drag returns the horizontal and the vertical air drag.
+func (cloth *Cloth) Drag() (x, y float64) {
+	return cloth.dragX, cloth.dragY
+}
+
+// UseXPBD switches between the XPBD and the default position-based constraint solver.
+func (cloth *Cloth) UseXPBD(on bool) {
+	cloth.solver = solverPBD
+	if on {
+		cloth.solver = solverXPBD
+	}
+}
+
+// SetCompliance sets the XPBD compliance (inverse stiffness) of every stick.
+func (cloth *Cloth) SetCompliance(compliance float64) {
+	cloth.compliance = compliance
+	for _, c := range cloth.constraints {
+		c.compliance = compliance
+	}
+}
+
+// SetIterations sets the number of constraint solver iterations per simulation step.
+func (cloth *Cloth) SetIterations(n int) {
+	cloth.iterations = maxInt(n, 1)
+}
+
+// Settled reports whether the cloth has stopped moving in the last simulation step. The cloth blown
+// by the wind model or swayed by the underwater current is never settled, since they are meant to keep it moving.
+func (cloth *Cloth) Settled() bool {
+	if cloth.windModel.Enabled && cloth.windModel.Strength != 0 || cloth.underwater {
+		return false
+	}
+	return cloth.motion < idleMotion
+}
+
+// Draw draws the cloth sticks and the mouse focus area. The particle positions are interpolated
+// between the previous and the current simulation step by `alpha`, which should be 1 for drawing
+// the current state.
+func (cloth *Cloth) Draw(gtx layout.Context, mouse *Mouse, alpha float64) {
+	dragForce := float32(mouse.getForce() * 0.75)
+	clothColor := color.NRGBA{R: 0x55, A: 0xff}
+	// Convert the RGB color to HSL based on the applied force over the mouse focus area.
+	col := LinearFromSRGB(clothColor).HSLA().Lighten(dragForce).RGBA().SRGB()
+
+	// The shadow is cast onto the floor behind everything else.
+	if cloth.shadow {
+		cloth.drawShadow(gtx, alpha)
+	}
+	for _, o := range cloth.obstacles {
+		o.draw(gtx)
+	}
+
+	// The trails are left behind the cloth.
+	if cloth.trails {
+		cloth.drawTrails(gtx, alpha)
+	}
+
+	var path clip.Path
+	// The presets without a grid, like the ropes, have no cells to fill, so their sticks are drawn.
+	switch {
+	case cloth.depth:
+		cloth.draw3D(gtx, alpha)
+	case cloth.texture != nil && cloth.drawTexture(gtx, alpha):
+	case cloth.fill && cloth.drawFill(gtx, alpha):
+	case cloth.render == renderStrain:
+		cloth.drawStrain(gtx, alpha)
+	case cloth.render == renderRainbow:
+		cloth.drawRainbow(gtx, alpha)
+	case cloth.render == renderGlow:
+		cloth.drawGlow(gtx, alpha)
+	default:
+		path.Begin(gtx.Ops)
+
+		// For performance reasons we draw the sticks as a single clip path instead of multiple clips paths.
+		// The performance improvement is considerable compared to the multiple clip paths rendered separately.
+		for _, c := range cloth.constraints {
+			if c.p1.isActive && c.kind == stickStructural {
+				cloth.addStick(&path, c, alpha)
+			}
+		}
+
+		cloth.fillSticks(gtx, cloth.color, &path)
+	}
+
+	cloth.drawFrays(gtx)
+	if cloth.burst != nil {
+		cloth.burst.Draw(gtx, cloth)
+	}
+
+	// The flat overlays wouldn't line up with the cloth seen by the orbiting camera.
+	if cloth.depth {
+		return
+	}
+	if cloth.dots {
+		cloth.drawDots(gtx, alpha)
+	}
+
+	// Here we are drawing the mouse focus area in a separate clip path,
+	// because the color used for highlighting the selected area
+	// should be different than the cloth's default color.
+	for _, c := range cloth.constraints {
+		if (c.p1.isActive && c.p1.highlighted) &&
+			(c.p2.isActive && c.p2.highlighted) && c.kind == stickStructural {
+			path.Begin(gtx.Ops)
+
+			cloth.addStick(&path, c, alpha)
+
+			c.color = color.NRGBA{R: col.R, A: col.A}
+
+			cloth.fillSticks(gtx, c.color, &path)
+		}
+	}
+
+	// The ball is drawn in front of the cloth it's pushing.
+	if cloth.ballOn {
+		cloth.ball.fill(gtx, ballColor)
+	}
+	for _, b := range cloth.bodies {
+		b.draw(gtx, cloth, alpha)
+	}
+
+	// The sticks which are about to tear are flashing white as a warning.
+	for _, c := range cloth.constraints {
+		if c.p1.isActive && c.flash > 0.05 && c.kind == stickStructural {
+			path.Begin(gtx.Ops)
+			cloth.addStick(&path, c, alpha)
+
+			cloth.fillSticks(gtx, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: uint8(c.flash * 0xff)}, &path)
+		}
+	}
+}
+
+// SetGravity sets the gravity acceleration vector.
+func (c *Cloth) SetGravity(x, y float64) {
+	c.forces.GravityX, c.forces.GravityY = x, y
+}
+
+// Gravity returns the gravity acceleration vector.
+func (c *Cloth) Gravity() (x, y float64) {
+	return c.forces.GravityX, c.forces.GravityY
+}
+
+// GravityMagnitude returns the strength of the gravity.
+func (c *Cloth) GravityMagnitude() float64 {
+	return math.Hypot(c.forces.GravityX, c.forces.GravityY)
+}
+
+// SetGravityMagnitude changes the strength of the gravity keeping its direction.
+// The magnitude is clamped between zero and the maximum gravity force.
+// Without a direction (zero gravity) the gravity points downward.
+func (c *Cloth) SetGravityMagnitude(m float64) {
+	m = math.Max(0, math.Min(m, maxGravity))
+	current := c.GravityMagnitude()
+	if current == 0 {
+		c.SetGravity(0, m)
+		return
+	}
+	c.SetGravity(c.forces.GravityX/current*m, c.forces.GravityY/current*m)
+}
+
+// SetGravityDirection turns the gravity towards the {x, y} direction keeping its magnitude,
+// e.g. following the tilt of the device. The zero vector turns the gravity downward.
+func (c *Cloth) SetGravityDirection(x, y float64) {
+	magnitude := c.GravityMagnitude()
+	dist := math.Hypot(x, y)
+	if dist == 0 {
+		c.SetGravity(0, magnitude)
+		return
+	}
+	c.SetGravity(x/dist*magnitude, y/dist*magnitude)
+}
+
+// SetWind sets the wind acceleration vector.
+func (c *Cloth) SetWind(x, y float64) {
+	c.forces.WindX, c.forces.WindY = x, y
+}
+
+// TogglePin pins up or releases the particle closest to the {x, y} position within the radius `r`.
+// It reports whether a particle has been found.
+func (c *Cloth) TogglePin(x, y, r float64) bool {
+	i := c.nearest(x, y, r)
+	if i < 0 {
+		return false
+	}
+	c.applyEdit(&pinEdit{p: c.particles[i], pinned: !c.particles[i].pinX})
+	return true
+}
+
+// PinAlong pins up every particle within the distance `r` from the segment
+// between the {x0, y0} and {x1, y1} points, e.g. along a stroke drawn by the pointer.
+func (c *Cloth) PinAlong(x0, y0, x1, y1, r float64) {
+	for _, p := range c.particles {
+		if p.isActive && !p.pinX && p.col >= 0 && segmentDistance(p.x, p.y, x0, y0, x1, y1) < r {
+			c.applyEdit(&pinEdit{p: p, pinned: true})
+		}
+	}
+}
+
+// nearest returns the index of the active particle closest to the {x, y} position
+// within the radius `r`, or -1 if there is no such particle.
+func (c *Cloth) nearest(x, y, r float64) int {
+	closest := -1
+	for i, p := range c.particles {
+		if d := distance(p.x-x, p.y-y); p.isActive && d < r {
+			closest, r = i, d
+		}
+	}
+	return closest
+}
+
+// applyEdit applies a destructive edit on the cloth and records it into the undo history.
+func (c *Cloth) applyEdit(e Edit) {
+	e.Apply(c)
+	c.history.Record(e)
+	// The changed structure of the cloth is disturbing the sleeping particles.
+	c.wake()
+}
+
+// Translate moves the whole cloth, including the pinned and the sliding pins, by the {dx, dy} vector.
+// The previous positions are moved too, so the cloth keeps its motion.
+func (c *Cloth) Translate(dx, dy int) {
+	fx, fy := float64(dx), float64(dy)
+	for _, p := range c.particles {
+		p.x, p.y = p.x+fx, p.y+fy
+		p.px, p.py = p.px+fx, p.py+fy
+		p.lx, p.ly = p.lx+fx, p.ly+fy
+		p.railY += fy
+	}
+	c.origin = c.origin.Add(image.Pt(dx, dy))
+}
+
+// Reset resets the cloth to the initial state.
+func (c *Cloth) Reset(startX, startY int) {
+	c.constraints = nil
+	c.particles = nil
+	c.isInitialized = false
+	c.history.Clear()
+
+	c.Init(startX, startY)
+}
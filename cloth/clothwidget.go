@@ -0,0 +1,943 @@
+package cloth
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"time"
+
+	"gioui.org/f32"
+	"gioui.org/io/event"
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+
+	"github.com/loov/hrtime"
+)
+
+// indicatorPressure is the time in seconds the mouse button must be held down
+// for filling up the focus area indicator.
+const indicatorPressure = 2
+
+// indicatorGap is the distance of the force charge arc from the focus area.
+const indicatorGap = 4
+
+// slowMotion is the speed of the simulation while the slow motion key is held down.
+const slowMotion = 0.2
+
+// ClothWidget is a live, tearable cloth which can be laid out like any other Gio widget.
+// The cloth is sized to the constraints it gets on the first layout, it handles the
+// pointer and key events scoped to its own area and it steps the physics based on the frame time.
+// The key events are handled only while the widget has the keyboard focus, which it gets
+// when it's clicked, so it doesn't steal the keystrokes meant for the host application.
+type ClothWidget struct {
+	// Theme is used for drawing the debug and status overlay. It's optional.
+	Theme *material.Theme
+	// Tag is the tag the input handlers are registered for. It defaults to the widget itself.
+	Tag event.Tag
+	// Area is the region reacting to the pointer events, relative to the widget.
+	// The zero value means the whole widget area.
+	Area image.Rectangle
+	// PassThrough lets the pointer events also reach the handlers underneath the cloth.
+	PassThrough bool
+	// OnTension is called when the maximum tension of the cloth crosses the warning threshold,
+	// signaling an imminent tear. It can be used for haptic or audio feedback.
+	OnTension func(tension float64)
+
+	config   Config
+	cloth    *Cloth
+	mouse    *Mouse
+	timeline *Timeline
+	stepper  *Stepper
+	idle     *Idle
+	governor *Governor
+	preset   *Preset
+	menu     *Menu
+	toolbar  *Toolbar
+	camera   *Camera
+	// texture is the texture image of the config, which is uploaded only once for every rebuilt cloth.
+	texture *paint.ImageOp
+
+	size       image.Point // the size of the widget in pixels
+	world      image.Point // the size of the widget in the world units (Dp)
+	forces     Forces
+	initTime   time.Time
+	snapTime   time.Time
+	scrollY    unit.Dp
+	isDragging bool
+	paused     bool
+	focused    bool
+	focus      bool
+	tense      bool
+	moveBall   bool
+	resized    bool // the cloth has been moved by a resize which is not captured yet
+	repairing  bool // the repair key is held down
+	charged    bool // the charged field around the cursor is on
+	hover      bool // the pointer is over the widget
+	attracting bool // the attract key is held down
+	pointing   bool // the primary pointer is pressed
+	primary    pointer.ID
+	tool       int  // the tool of the primary pointer
+	help       bool // the help overlay is shown
+	stepped    bool // the paused simulation has been advanced by a single step
+	stroked    bool // the pin tool has been dragged since the press
+	shakeDir   float64
+	dark       bool // the dark color scheme is used
+	palette    *Palette
+	scheme     Scheme
+	background Background
+	strainView bool // the sticks are colored by their length error for tuning the solver
+	winding    bool // the winding of the cells is shown for diagnosing the flipped cells
+	aiming     bool // a projectile is being aimed from the aim position
+	aim        f32.Point
+	offCloth   bool // the first finger has been pressed away from the cloth
+	pinching   bool // the camera is zoomed by two fingers
+	pinchID    pointer.ID
+	pinchA     f32.Point // the screen position of the first pinching finger
+	pinchB     f32.Point // the screen position of the second pinching finger
+	panning    bool      // the camera is panned with the middle button
+	panAt      f32.Point
+	lasso      []f32.Point // the outline of the selection being drawn
+	selection  []int       // the indices of the selected particles
+	lastPress  time.Duration
+	clickPos   f32.Point
+}
+
+// NewClothWidget creates a new cloth widget with the settings of the config.
+// The theme is optional, without it the overlays, the toolbar and the menus are not shown.
+func NewClothWidget(th *material.Theme, config Config) *ClothWidget {
+	// The time step and the number of sub-steps and iterations must not depend on the speed of the machine.
+	if config.Deterministic {
+		config.FrameBudget = 0
+		if config.PhysicsHz == 0 {
+			config.PhysicsHz = physicsRate
+		}
+	}
+	// The widget sizes the cloth by its preset, so the missing preset is the default scene.
+	if config.Preset == nil {
+		config.Preset = presets["cloth"]
+	}
+	// Throttling the rendering requires the physics to run at its own rate.
+	hz := config.PhysicsHz
+	if config.RenderFPS > 0 && hz == 0 {
+		hz = physicsRate
+	}
+
+	camera := newCamera()
+	w := &ClothWidget{
+		Theme:    th,
+		config:   config,
+		mouse:    &Mouse{maxScrollY: unit.Dp(200), camera: camera},
+		timeline: NewTimeline(config.Snapshots),
+		stepper:  NewStepper(hz),
+		idle:     NewIdle(config.IdleAfter),
+		governor: NewGovernor(config.FrameBudget, config.MinSubSteps, config.MaxSubSteps, config.MinIterations, config.MaxIterations),
+		preset:   config.Preset,
+		menu:     newMenu(),
+		toolbar:  newToolbar(),
+		camera:   camera,
+		palette:  config.Palette,
+		dark:     config.Dark,
+	}
+	if config.SolverIterations > 0 {
+		w.governor.SetIterations(config.SolverIterations)
+	}
+	w.mouse.unproject = func(pt f32.Point) f32.Point {
+		return w.cloth.unproject(pt, w.world)
+	}
+	if config.Texture != nil {
+		texture := paint.NewImageOp(config.Texture)
+		w.texture = &texture
+	}
+	w.applyScheme()
+	w.background = config.Background
+	w.strainView = config.DebugStrain
+	w.winding = config.DebugWinding
+	return w
+}
+
+// Layout handles the input events, advances the physics and draws the cloth filling the maximum constraints.
+func (w *ClothWidget) Layout(gtx layout.Context) layout.Dimensions {
+	start := hrtime.Now()
+	w.size = gtx.Constraints.Max
+	// The simulation runs in device independent units, so the cloth looks and feels the same on every screen density.
+	density := gtx.Metric.PxPerDp
+	if density <= 0 {
+		density = 1
+	}
+	w.world = image.Pt(int(float32(w.size.X)/density), int(float32(w.size.Y)/density))
+	w.camera.density = density
+	mouse, timeline := w.mouse, w.timeline
+
+	if w.cloth == nil {
+		w.newCloth()
+	}
+	cloth := w.cloth
+
+	if !cloth.isInitialized {
+		cloth.Init(w.startPosition())
+	}
+	w.relayout()
+
+	// Keep the drawing inside the widget area.
+	defer clip.Rect{Max: w.size}.Push(gtx.Ops).Pop()
+
+	tag := w.tag()
+	area := w.Area
+	if area.Empty() {
+		area = image.Rectangle{Max: w.size}
+	}
+	inputArea := clip.Rect(area).Push(gtx.Ops)
+	if w.PassThrough {
+		pass := pointer.PassOp{}.Push(gtx.Ops)
+		defer pass.Pop()
+	}
+	pointer.InputOp{
+		Tag:   tag,
+		Types: pointer.Enter | pointer.Leave | pointer.Scroll | pointer.Move | pointer.Press | pointer.Drag | pointer.Release | pointer.Type(pointer.ButtonPrimary) | pointer.Type(pointer.ButtonSecondary),
+		ScrollBounds: image.Rectangle{
+			Min: image.Point{
+				X: 0,
+				Y: -30,
+			},
+			Max: image.Point{
+				X: 0,
+				Y: 30,
+			},
+		},
+	}.Add(gtx.Ops)
+
+	key.InputOp{
+		Tag:  tag,
+		Keys: key.Set(key.NameCtrl+"|"+key.NameAlt+"|") + bindingKeys(),
+	}.Add(gtx.Ops)
+	if w.focus {
+		key.FocusOp{Tag: tag}.Add(gtx.Ops)
+		w.focus = false
+	}
+	inputArea.Pop()
+
+	mouse.setMetric(gtx.Metric)
+	if mouse.getLeftButton() {
+		deltaTime := time.Now().Sub(w.initTime)
+		mouse.increaseForce(deltaTime.Seconds())
+		w.idle.Wake()
+	}
+	// The repair is applied by the physics steps, so it's recorded and replayed with the other inputs.
+	if mouse.stitching {
+		w.idle.Wake()
+	}
+	// Changing the forces from outside (e.g. gamepad) should also wake up the idle cloth.
+	if cloth.forces != w.forces {
+		w.forces = cloth.forces
+		w.idle.Wake()
+	}
+
+	if gtx.Queue != nil {
+		for _, ev := range gtx.Queue.Events(tag) {
+			w.idle.Wake()
+			switch ev := ev.(type) {
+			case key.FocusEvent:
+				w.focused = ev.Focus
+				if !ev.Focus {
+					w.releaseHolds()
+				}
+			case key.Event:
+				// Without focus the key events are only falling through from the host application.
+				if w.focused {
+					w.handleKey(ev)
+				}
+			case pointer.Event:
+				w.handlePointer(ev)
+			}
+		}
+	}
+	if !w.paused && w.config.SnapshotInterval > 0 && time.Since(w.snapTime) >= w.config.SnapshotInterval {
+		timeline.Capture(cloth)
+		w.snapTime = time.Now()
+	}
+
+	w.drawBackground(gtx)
+
+	asleep := w.idle.Update(gtx.Now, cloth.Settled())
+	if asleep {
+		w.stepper.Pause()
+	}
+
+	alpha := 1.0
+	if !w.paused && !asleep {
+		var (
+			steps int
+			delta float64
+		)
+		steps, delta, alpha = w.stepper.Advance(gtx.Now)
+
+		physicsStart := time.Now()
+		subSteps := w.governor.SubSteps()
+		cloth.SetIterations(w.governor.Iterations())
+		for i := 0; i < steps; i++ {
+			cloth.keepPositions()
+			for j := 0; j < subSteps; j++ {
+				timeline.Step(cloth, mouse, w.world.X, w.world.Y, delta/float64(subSteps))
+			}
+		}
+		w.governor.Update(time.Since(physicsStart))
+	}
+	if w.paused {
+		w.editPaused()
+	}
+	w.checkTension()
+	// The cloth and the pointer feedback are drawn in the world coordinates, transformed by the camera.
+	camera := op.Affine(w.camera.transform()).Push(gtx.Ops)
+	wgtx := gtx
+	wgtx.Constraints = layout.Exact(w.world)
+	cloth.Draw(wgtx, mouse, alpha)
+	if w.strainView {
+		cloth.drawStrainView(wgtx, w.config.Gradient, alpha)
+	}
+	if w.winding {
+		cloth.drawWinding(wgtx, alpha)
+	}
+	w.drawIndicator(wgtx)
+	w.drawSelection(wgtx)
+	camera.Pop()
+
+	w.drawOverlay(gtx, start)
+	w.drawLegend(gtx)
+	if w.Theme != nil {
+		w.toolbar.Layout(gtx, w)
+		w.drawHelp(gtx)
+		w.menu.Layout(gtx, w)
+	}
+
+	// The idle, the paused or the sleeping cloth is only changed by the input events, which are redrawing
+	// the widget anyway, so it's not redrawn until then, unless the pointer is held down or the rainbow is flowing.
+	resting := asleep || w.paused || cloth.Asleep()
+	if !resting || mouse.getLeftButton() || w.pointing || cloth.RenderMode() == renderRainbow {
+		if fps := w.config.RenderFPS; fps > 0 {
+			op.InvalidateOp{At: gtx.Now.Add(time.Second / time.Duration(fps))}.Add(gtx.Ops)
+		} else {
+			op.InvalidateOp{}.Add(gtx.Ops)
+		}
+	}
+	return layout.Dimensions{Size: w.size}
+}
+
+// Cloth returns the simulated cloth. It's nil until the widget has been laid out.
+func (w *ClothWidget) Cloth() *Cloth {
+	return w.cloth
+}
+
+// checkTension fires the tension event when the maximum tension crosses
+// the warning threshold and flashes the sticks of the pulled cloth which are about to tear.
+func (w *ClothWidget) checkTension() {
+	warnAt := w.config.TensionWarning
+	if warnAt <= 0 {
+		return
+	}
+	tension := w.cloth.MaxTension()
+	tense := tension >= warnAt
+	if tense && !w.tense && w.OnTension != nil {
+		w.OnTension(tension)
+	}
+	w.tense = tense
+	w.cloth.flashStrained(warnAt, w.mouse.pulling())
+}
+
+// Focus requests the keyboard focus for the widget.
+func (w *ClothWidget) Focus() {
+	w.focus = true
+}
+
+// tag returns the tag of the input handlers.
+func (w *ClothWidget) tag() event.Tag {
+	if w.Tag != nil {
+		return w.Tag
+	}
+	return w
+}
+
+// Reset resets the cloth to its initial state.
+func (w *ClothWidget) Reset() {
+	if w.cloth == nil {
+		return
+	}
+	w.cloth.Reset(w.startPosition())
+	w.timeline.Capture(w.cloth)
+	w.idle.Wake()
+}
+
+// newCloth creates the cloth of the preset with the settings of the config.
+func (w *ClothWidget) newCloth() {
+	cfg := w.config
+	w.cloth = NewCloth(int(float64(w.world.X)*w.preset.Width), int(float64(w.world.Y)*w.preset.Height), 8, 0.99, w.scheme.Cloth)
+	w.cloth.history = NewHistory(cfg.UndoDepth)
+	w.cloth.jitter, w.cloth.seed = cfg.Jitter, cfg.Seed
+	w.cloth.SetGravity(0, cfg.Gravity)
+	w.cloth.SetSelfCollision(cfg.SelfCollision)
+	w.cloth.UseXPBD(cfg.XPBD)
+	w.cloth.SetCompliance(cfg.Compliance)
+	w.cloth.SetDrag(cfg.DragX, cfg.DragY)
+	w.cloth.SetStiffness(cfg.Shear, cfg.Bend)
+	w.cloth.SetTearThreshold(cfg.TearThreshold)
+	w.cloth.SetPlastic(cfg.Plastic)
+	w.cloth.SetGrain(cfg.Warp, cfg.Weft)
+	w.cloth.SetFloor(cfg.Floor)
+	w.cloth.SetWalls(cfg.Walls)
+	w.cloth.SetUnderwater(cfg.Underwater)
+	w.cloth.SetField(cfg.FieldRadius, cfg.FieldStrength)
+	w.cloth.SetFalloff(cfg.Weakening, cfg.WeakeningAmount)
+	w.cloth.SetDiagnostics(cfg.DebugSolver)
+	w.cloth.SetSleeping(cfg.Sleep)
+	w.cloth.UseSprings(cfg.Springs, cfg.SpringK, cfg.SpringDamping)
+	w.cloth.SetFriction(cfg.Friction)
+	w.cloth.SetStrainLimit(cfg.StrainLimit)
+	w.cloth.sliding = cfg.SlidingPins
+	w.cloth.SetFill(cfg.Fill)
+	w.cloth.texture = w.texture
+	w.cloth.SetDots(cfg.Dots)
+	w.cloth.SetTrails(cfg.Trails)
+	w.cloth.SetShadow(cfg.Shadow)
+	w.cloth.SetFraying(cfg.Fraying)
+	w.cloth.SetBursts(cfg.Bursts)
+	w.cloth.SetDepth(cfg.Depth)
+	w.cloth.SetStroke(cfg.StrokeWidth, cfg.LineCap)
+	if cfg.TensionWidth {
+		w.cloth.SetTensionWidth(cfg.MinLineWidth, cfg.MaxLineWidth)
+	}
+	w.cloth.SetRenderMode(cfg.RenderMode)
+	w.cloth.SetStrainGradient(cfg.Gradient)
+	rows := w.cloth.Rows()
+	w.cloth.SetMassFunc(func(col, row int) float64 {
+		if row == rows-1 {
+			return cfg.HemMass
+		}
+		return 1
+	})
+	for _, o := range cfg.Obstacles {
+		w.cloth.AddObstacle(o)
+	}
+	w.cloth.SetWindModel(cfg.Wind)
+	w.cloth.SetPreset(w.preset)
+	w.forces = w.cloth.forces
+}
+
+// Preset returns the scene preset of the cloth.
+func (w *ClothWidget) Preset() *Preset {
+	return w.preset
+}
+
+// SetPreset switches the cloth to a new scene preset. The cloth is rebuilt from
+// the command line flags, so the settings changed at runtime are not kept.
+func (w *ClothWidget) SetPreset(p *Preset) {
+	if p == nil {
+		p = presets["cloth"]
+	}
+	w.preset, w.selection = p, nil
+	// The recorded frames and snapshots belong to the previous cloth.
+	w.timeline = NewTimeline(w.config.Snapshots)
+	w.idle.Wake()
+	if w.cloth != nil {
+		w.newCloth()
+	}
+}
+
+// relayout keeps the cloth centered when the widget is resized, moving it along with its pinned row
+// to the start position of the new size. The moved cloth is captured only once the size has settled,
+// so resizing the window by dragging doesn't flood the timeline with a snapshot for every frame.
+func (w *ClothWidget) relayout() {
+	x, y := w.startPosition()
+	if d := image.Pt(x, y).Sub(w.cloth.origin); d != (image.Point{}) {
+		w.cloth.Translate(d.X, d.Y)
+		w.resized = true
+		w.idle.Wake()
+	} else if w.resized {
+		w.timeline.Capture(w.cloth)
+		w.resized = false
+	}
+}
+
+// startPosition returns the top-left position of the cloth centered horizontally in the widget.
+// The vertical position is set by the drop height, clamped so that the whole cloth is visible.
+func (w *ClothWidget) startPosition() (int, int) {
+	startX := w.world.X/2 - w.cloth.width/2
+	dropY := w.config.DropY
+	startY := int(dropY)
+	if dropY <= 1 {
+		startY = int(float64(w.world.Y) * dropY)
+	}
+	if maxY := w.world.Y - w.cloth.height; startY > maxY {
+		startY = maxY
+	}
+	if startY < 0 {
+		startY = 0
+	}
+	return startX, startY
+}
+
+// setTool switches the primary pointer to the tool, interrupting the action of the previous tool.
+func (w *ClothWidget) setTool(tool int) {
+	w.tool = tool
+	w.mouse.setCutting(false)
+	w.mouse.setStitching(false)
+	w.mouse.unthread()
+}
+
+// toggleTool switches the primary pointer to the tool, or back to pushing the cloth if it's already selected.
+func (w *ClothWidget) toggleTool(tool int) {
+	if w.tool == tool {
+		tool = toolPush
+	}
+	w.setTool(tool)
+}
+
+// Undo reverts the last tear or pin edit of the cloth.
+func (w *ClothWidget) Undo() {
+	w.cloth.history.Undo(w.cloth)
+	w.timeline.Capture(w.cloth)
+}
+
+// Redo reapplies the last edit reverted by Undo.
+func (w *ClothWidget) Redo() {
+	w.cloth.history.Redo(w.cloth)
+	w.timeline.Capture(w.cloth)
+}
+
+// togglePause pauses or resumes the simulation.
+func (w *ClothWidget) togglePause() {
+	w.paused, w.stepped = !w.paused, false
+	w.stepper.Pause()
+	if !w.paused {
+		// The cloth might have been edited while paused.
+		w.timeline.Capture(w.cloth)
+	}
+}
+
+// editPaused applies the cutting and the repair tools to the paused cloth, which are otherwise applied
+// during the physics step, so a tear pattern can be prepared before letting the cloth move.
+func (w *ClothWidget) editPaused() {
+	mouse, cloth := w.mouse, w.cloth
+	if mouse.cutting {
+		cloth.cut(mouse.sx, mouse.sy, mouse.x, mouse.y)
+	}
+	if mouse.stitching {
+		cloth.repair(mouse.x, mouse.y, mouse.getFocusArea())
+	}
+	if mouse.getRightButton() {
+		for _, p := range cloth.particles {
+			if p.isActive && mouse.sweepDistance(p.x, p.y) < mouse.getFocusArea() {
+				cloth.applyEdit(&tearEdit{p: p})
+			}
+		}
+	}
+	mouse.endSweep()
+}
+
+// handlePointer handles the mouse and touch events.
+func (w *ClothWidget) handlePointer(ev pointer.Event) {
+	mouse := w.mouse
+
+	if w.handlePinch(ev) || w.handlePan(ev) {
+		return
+	}
+	// Holding CTRL the scrolling zooms the camera around the pointer.
+	if ev.Type == pointer.Scroll && ev.Modifiers.Contain(key.ModCtrl) {
+		w.camera.zoomAt(ev.Position, float32(math.Exp(-float64(ev.Scroll.Y)*zoomRate)))
+		return
+	}
+	if w.handleTouch(ev) {
+		return
+	}
+	// With a theme to draw it the right click opens the context menu, which is closed by any other click.
+	if w.Theme != nil && ev.Type == pointer.Press {
+		if w.menu.open {
+			w.menu.open = false
+			return
+		}
+		if ev.Buttons == pointer.ButtonSecondary {
+			w.menu.show(int(ev.Position.X), int(ev.Position.Y))
+			return
+		}
+	}
+	// Dragging the ball takes over the pointer, so it's not interacting with the cloth directly.
+	if ball, on := w.cloth.Ball(); on && ev.Type == pointer.Press {
+		pos := mouse.getCurrentPosition(ev)
+		if ball.contains(float64(pos.X), float64(pos.Y)) {
+			w.moveBall = true
+			w.Focus()
+		}
+	}
+	// The charged field attracts the particles, or repels them while the ALT key is held down.
+	if w.charged && !w.attracting {
+		if ev.Modifiers.Contain(key.ModAlt) {
+			mouse.setField(-1)
+		} else {
+			mouse.setField(1)
+		}
+	}
+
+	// Holding the repair key turns the pointer into a repair tool, which stitches the holes together.
+	if w.repairing || mouse.stitching {
+		pos := mouse.getCurrentPosition(ev)
+		mouse.updatePosition(float64(pos.X), float64(pos.Y))
+		switch ev.Type {
+		case pointer.Press:
+			mouse.setStitching(true)
+			w.Focus()
+		case pointer.Release, pointer.Cancel:
+			mouse.setStitching(false)
+			w.cloth.history.Commit()
+		}
+		return
+	}
+	// The scissors are cutting every stick crossed by the cursor, while the button is held down.
+	if w.tool == toolCut {
+		pos := mouse.getCurrentPosition(ev)
+		mouse.updatePosition(float64(pos.X), float64(pos.Y))
+		switch ev.Type {
+		case pointer.Press:
+			mouse.endSweep()
+			mouse.setCutting(true)
+			w.Focus()
+		case pointer.Release, pointer.Cancel:
+			mouse.setCutting(false)
+			w.cloth.history.Commit()
+		}
+		return
+	}
+	// The needle holds the particle closest to the cursor, while the button is held down.
+	if w.tool == toolPull {
+		pos := mouse.getCurrentPosition(ev)
+		mouse.updatePosition(float64(pos.X), float64(pos.Y))
+		switch ev.Type {
+		case pointer.Press:
+			if i := w.cloth.nearest(mouse.x, mouse.y, needleDist); i >= 0 {
+				mouse.thread(i)
+			}
+			w.Focus()
+		case pointer.Release, pointer.Cancel:
+			mouse.unthread()
+		}
+		return
+	}
+	// The pin tool pins every particle along the stroke drawn with it, while a click without
+	// dragging is pinning up or releasing a single particle.
+	if w.tool == toolPin {
+		pos := mouse.getCurrentPosition(ev)
+		mouse.updatePosition(float64(pos.X), float64(pos.Y))
+		switch ev.Type {
+		case pointer.Press:
+			w.stroked = false
+			w.Focus()
+		case pointer.Drag:
+			w.stroked = true
+			w.cloth.PinAlong(mouse.px, mouse.py, mouse.x, mouse.y, clothPinDist)
+		case pointer.Release:
+			if !w.stroked {
+				w.cloth.TogglePin(mouse.x, mouse.y, clothPinDist)
+			}
+			w.cloth.history.Commit()
+			w.timeline.Capture(w.cloth)
+		}
+		return
+	}
+	// The grabbed pinned particles are following the pointer until it's released.
+	if len(mouse.hanger) > 0 {
+		pos := mouse.getCurrentPosition(ev)
+		mouse.updatePosition(float64(pos.X), float64(pos.Y))
+		if ev.Type == pointer.Release || ev.Type == pointer.Cancel {
+			mouse.drop()
+		}
+		return
+	}
+	// The select tool selects the particles inside the lasso drawn with it, while a click clears the selection.
+	if w.tool == toolSelect {
+		pos := mouse.getCurrentPosition(ev)
+		mouse.updatePosition(float64(pos.X), float64(pos.Y))
+		switch ev.Type {
+		case pointer.Press:
+			w.lasso = []f32.Point{pos}
+			w.Focus()
+		case pointer.Drag:
+			w.lasso = append(w.lasso, pos)
+		case pointer.Release:
+			w.selection = w.cloth.selectInside(w.lasso)
+			w.lasso = nil
+		case pointer.Cancel:
+			w.lasso = nil
+		}
+		return
+	}
+	// The projectile is thrown on release, in the direction and with the speed of the drag.
+	if w.tool == toolThrow {
+		pos := mouse.getCurrentPosition(ev)
+		mouse.updatePosition(float64(pos.X), float64(pos.Y))
+		switch ev.Type {
+		case pointer.Press:
+			w.aim, w.aiming = pos, true
+			w.Focus()
+		case pointer.Release:
+			if w.aiming {
+				v := pos.Sub(w.aim).Mul(projectileSpeed)
+				w.cloth.Throw(float64(pos.X), float64(pos.Y), float64(v.X), float64(v.Y))
+				w.timeline.Capture(w.cloth)
+			}
+			w.aiming = false
+		case pointer.Cancel:
+			w.aiming = false
+		}
+		return
+	}
+	if w.moveBall {
+		switch ev.Type {
+		case pointer.Drag:
+			pos := mouse.getCurrentPosition(ev)
+			w.cloth.MoveBall(float64(pos.X), float64(pos.Y))
+		case pointer.Release, pointer.Cancel:
+			w.moveBall = false
+		}
+		return
+	}
+
+	switch ev.Type {
+	case pointer.Enter:
+		w.hover = true
+	case pointer.Leave:
+		w.hover = false
+	case pointer.Scroll:
+		w.scrollY += mouse.getScrollDelta(ev)
+		if w.scrollY < 0 {
+			w.scrollY = 0
+		} else if w.scrollY > mouse.maxScrollY {
+			w.scrollY = mouse.maxScrollY
+		}
+		mouse.setScrollY(w.scrollY)
+	case pointer.Move:
+		pos := mouse.getCurrentPosition(ev)
+		mouse.updatePosition(float64(pos.X), float64(pos.Y))
+	case pointer.Press:
+		if ev.Modifiers == key.ModShift {
+			pos := mouse.getCurrentPosition(ev)
+			w.cloth.AddObstacle(Circle{X: float64(pos.X), Y: float64(pos.Y), R: obstacleRadius})
+			w.Focus()
+			return
+		}
+		if ev.Modifiers == key.ModCtrl {
+			pos := mouse.getCurrentPosition(ev)
+			if w.cloth.TogglePin(float64(pos.X), float64(pos.Y), clothPinDist) {
+				w.cloth.history.Commit()
+				w.timeline.Capture(w.cloth)
+			}
+			w.Focus()
+			return
+		}
+		// Only the plain primary clicks are counted, so a double click with a modifier doesn't explode the cloth.
+		primary := ev.Modifiers == 0 && mouse.getButtons(ev) == pointer.ButtonPrimary
+		if primary && w.doubleClick(ev) {
+			pos := mouse.getCurrentPosition(ev)
+			w.cloth.Explode(float64(pos.X), float64(pos.Y), explosionRadius, explosionImpulse, w.config.ExplosionTear)
+			w.timeline.Capture(w.cloth)
+			w.idle.Wake()
+		}
+		// A new press must not sweep the path from the previous pointer position, e.g. from a lifted finger.
+		pos := mouse.getCurrentPosition(ev)
+		mouse.updatePosition(float64(pos.X), float64(pos.Y))
+		mouse.endSweep()
+		// Grabbing a pinned particle with the push tool moves the whole hanger instead of dragging the cloth.
+		// The other tools and buttons are still acting on the pinned particles, e.g. tearing the pinned row.
+		if w.tool == toolPush && primary {
+			if hanger := w.cloth.hanger(mouse.x, mouse.y, clothPinDist); hanger != nil {
+				mouse.grab(hanger)
+				w.Focus()
+				return
+			}
+		}
+		mouse.setLeftButton()
+		w.initTime = time.Now()
+		w.Focus()
+	case pointer.Release, pointer.Cancel:
+		w.isDragging = false
+
+		w.cloth.history.Commit()
+		mouse.resetForce()
+		mouse.releaseLeftButton()
+		mouse.releaseRightButton()
+		mouse.setDragging(w.isDragging)
+	case pointer.Drag:
+		w.isDragging = true
+	}
+	buttons := mouse.getButtons(ev)
+	// The tear tool makes holes with the primary button, while the secondary one is opening the menu.
+	if w.tool == toolTear && buttons == pointer.ButtonPrimary {
+		buttons = pointer.ButtonSecondary
+	} else if w.Theme != nil && buttons == pointer.ButtonSecondary {
+		return
+	}
+	switch buttons {
+	case pointer.ButtonPrimary:
+		mouse.setLeftButton()
+		pos := mouse.getCurrentPosition(ev)
+		mouse.updatePosition(float64(pos.X), float64(pos.Y))
+		mouse.setDragging(w.isDragging)
+	case pointer.ButtonSecondary:
+		mouse.setRightButton()
+		pos := mouse.getCurrentPosition(ev)
+		mouse.updatePosition(float64(pos.X), float64(pos.Y))
+	}
+}
+
+// handleTouch tracks the fingers touching the screen while the primary pointer is already pressed.
+// These are dragging and tearing the cloth independently, so the cloth can be pinched or torn apart
+// with multiple fingers. It reports whether the event belongs to an additional touch.
+func (w *ClothWidget) handleTouch(ev pointer.Event) bool {
+	mouse := w.mouse
+	pos := mouse.getCurrentPosition(ev)
+	switch ev.Type {
+	case pointer.Press:
+		if !w.pointing {
+			w.pointing, w.primary = true, ev.PointerID
+			return false
+		}
+		if ev.Source != pointer.Touch || ev.PointerID == w.primary {
+			return false
+		}
+		mouse.addTouch(ev.PointerID, float64(pos.X), float64(pos.Y))
+		w.idle.Wake()
+		return true
+	case pointer.Drag:
+		if mouse.touch(ev.PointerID) != nil {
+			mouse.moveTouch(ev.PointerID, float64(pos.X), float64(pos.Y))
+			return true
+		}
+	case pointer.Release:
+		if mouse.touch(ev.PointerID) != nil {
+			mouse.removeTouch(ev.PointerID)
+			w.cloth.history.Commit()
+			return true
+		}
+		if ev.PointerID == w.primary {
+			w.pointing = false
+		}
+	case pointer.Cancel:
+		// The cancel event is ending all the pointers at once.
+		mouse.touches = nil
+		w.pointing = false
+	}
+	return false
+}
+
+// doubleClick reports whether the press event is the second click of a double click.
+func (w *ClothWidget) doubleClick(ev pointer.Event) bool {
+	pos := w.mouse.getCurrentPosition(ev)
+	double := ev.Time-w.lastPress < doubleClickTime &&
+		distance(float64(pos.X-w.clickPos.X), float64(pos.Y-w.clickPos.Y)) < doubleClickDist
+	w.lastPress, w.clickPos = ev.Time, pos
+	if double {
+		// A third click shouldn't trigger another explosion.
+		w.lastPress = 0
+	}
+	return double
+}
+
+// drawIndicator draws the focus area around the cursor, which is resized by scrolling. The outline is
+// tinted by the charge of the field and the area is filling up with the pressure of the held button.
+func (w *ClothWidget) drawIndicator(gtx layout.Context) {
+	if !w.hover {
+		return
+	}
+	m := w.mouse
+	r := m.getFocusArea()
+	center := w.viewed(m.x, m.y)
+	rect := image.Rect(int(float64(center.X)-r), int(float64(center.Y)-r), int(float64(center.X)+r), int(float64(center.Y)+r))
+
+	col := color.NRGBA{R: 0x55, G: 0x55, B: 0x55, A: 0x80}
+	switch m.field {
+	case 1:
+		col = color.NRGBA{R: 0x39, G: 0x8d, B: 0xd9, A: 0xc0}
+	case -1:
+		col = color.NRGBA{R: 0xd9, G: 0x5d, B: 0x39, A: 0xc0}
+	}
+	if level := math.Min(m.getForce()/indicatorPressure, 1); level > 0 {
+		fill := col
+		fill.A = uint8(level * 0x40)
+		paint.FillShape(gtx.Ops, fill, clip.Ellipse(rect).Op(gtx.Ops))
+
+		// The charge of the force is also shown by an arc growing clockwise around the focus area.
+		start := center.Sub(f32.Pt(0, float32(r+indicatorGap)))
+		var arc clip.Path
+		arc.Begin(gtx.Ops)
+		arc.MoveTo(start)
+		arc.Arc(center.Sub(start), center.Sub(start), float32(2*math.Pi*level))
+		paint.FillShape(gtx.Ops, col, clip.Stroke{Path: arc.End(), Width: 3}.Op())
+	}
+	paint.FillShape(gtx.Ops, col, clip.Stroke{Path: clip.Ellipse(rect).Path(gtx.Ops), Width: 1.5}.Op())
+
+	// The aimed projectile is thrown along the line from the aim position to the cursor.
+	if w.aiming {
+		var path clip.Path
+		path.Begin(gtx.Ops)
+		path.MoveTo(w.viewed(float64(w.aim.X), float64(w.aim.Y)))
+		path.LineTo(center)
+		paint.FillShape(gtx.Ops, col, clip.Stroke{Path: path.End(), Width: 1.5}.Op())
+	}
+}
+
+// viewed returns the position the {x, y} position of the flat cloth is seen at. The pointer positions
+// are mapped onto the flat cloth seen in perspective, so the pointer feedback is projected back under the pointer.
+func (w *ClothWidget) viewed(x, y float64) f32.Point {
+	if !w.cloth.depth {
+		return f32.Pt(float32(x), float32(y))
+	}
+	pt, _ := w.cloth.projector(w.world).project(x, y, 0)
+	return pt
+}
+
+// drawOverlay draws the debug and the status information over the cloth.
+func (w *ClothWidget) drawOverlay(gtx layout.Context, start time.Duration) {
+	if w.Theme == nil {
+		return
+	}
+	var overlay []string
+	if w.config.DebugFrame {
+		dragX, dragY := w.cloth.Drag()
+		overlay = append(overlay,
+			hrtime.Since(start).String(),
+			fmt.Sprintf("Frame %d, seed %d", w.timeline.frame, w.config.Seed),
+			fmt.Sprintf("Gravity %.0f", w.cloth.GravityMagnitude()),
+			fmt.Sprintf("Sub-steps %d, iterations %d", w.governor.SubSteps(), w.governor.Iterations()),
+			fmt.Sprintf("Drag %.3f, %.3f", dragX, dragY),
+			fmt.Sprintf("Tear threshold %.0f", w.cloth.TearThreshold()),
+		)
+	}
+	if w.config.DebugSolver || w.paused && w.stepped {
+		stats := w.cloth.Stats()
+		overlay = append(overlay,
+			fmt.Sprintf("Kinetic energy %.3g", stats.Kinetic),
+			fmt.Sprintf("Potential energy %.3g", stats.Potential),
+			fmt.Sprintf("Max strain %.1f%%", stats.MaxStrain*100),
+			fmt.Sprintf("Residual %.2e", stats.Residual),
+		)
+	}
+	if w.paused {
+		overlay = append(overlay, fmt.Sprintf("Paused at frame %d", w.timeline.frame))
+	}
+	if len(overlay) == 0 {
+		return
+	}
+	layout.Stack{}.Layout(gtx,
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			op.Offset(image.Pt(10, 10)).Add(gtx.Ops)
+			return layout.E.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				m := material.Label(w.Theme, unit.Sp(15), strings.Join(overlay, "\n"))
+				m.Color = w.scheme.Text
+				return m.Layout(gtx)
+			})
+		}))
+}
@@ -0,0 +1,83 @@
+package cloth
+
+import "image"
+
+const (
+	// selfCollisionDist is the minimum distance kept between the particles which are not
+	// direct neighbours in the cloth grid, as a fraction of the spacing.
+	selfCollisionDist = 0.6
+	// sheetCollisionDist is the minimum distance kept between the particles of different sheets,
+	// as a fraction of the spacing. It's larger than half of the grid cell diagonal,
+	// so the particles of a sheet can't slip through the grid of the other sheet.
+	sheetCollisionDist = 0.8
+)
+
+// collide pushes apart the particles which got closer than the minimum distance, so the layers
+// of a folded cloth and the separate sheets pile up instead of passing through each other. Only the particles
+// from the neighbouring cells of a spatial grid are compared, where the cell size is the largest minimum distance.
+func (c *Cloth) collide() {
+	cellSize := float64(c.spacing) * sheetCollisionDist
+	if c.grid == nil {
+		c.grid = make(map[image.Point][]int)
+	}
+	for cell, idx := range c.grid {
+		c.grid[cell] = idx[:0]
+	}
+	cellOf := func(p *Particle) image.Point {
+		return image.Pt(int(p.x/cellSize), int(p.y/cellSize))
+	}
+	for i, p := range c.particles {
+		if p.isActive {
+			cell := cellOf(p)
+			c.grid[cell] = append(c.grid[cell], i)
+		}
+	}
+
+	// The particles are visited in order, so the result doesn't depend on the map iteration order.
+	for i, p := range c.particles {
+		if !p.isActive {
+			continue
+		}
+		cell := cellOf(p)
+		for y := cell.Y - 1; y <= cell.Y+1; y++ {
+			for x := cell.X - 1; x <= cell.X+1; x++ {
+				for _, j := range c.grid[image.Pt(x, y)] {
+					if j <= i {
+						continue
+					}
+					q := c.particles[j]
+					minDist := float64(c.spacing) * sheetCollisionDist
+					if p.sheet == q.sheet {
+						if !c.selfCollide || absInt(p.col-q.col) <= 1 && absInt(p.row-q.row) <= 1 {
+							continue
+						}
+						minDist = float64(c.spacing) * selfCollisionDist
+					}
+					dx, dy := p.x-q.x, p.y-q.y
+					dist := distance(dx, dy)
+					if dist >= minDist || dist == 0 {
+						continue
+					}
+					mul := fround((minDist - dist) / dist * 0.5)
+					offsetX, offsetY := fround(dx*mul), fround(dy*mul)
+					if !p.pinX {
+						p.x += offsetX
+						p.y += offsetY
+					}
+					if !q.pinX {
+						q.x -= offsetX
+						q.y -= offsetY
+					}
+				}
+			}
+		}
+	}
+}
+
+// absInt returns the absolute value of x.
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
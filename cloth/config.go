@@ -0,0 +1,148 @@
+package cloth
+
+import (
+	"image"
+	"time"
+)
+
+// Config holds the settings the cloth widget creates its cloth with. The zero value is not usable,
+// the settings should start from DefaultConfig. The command line flags of the demo are mapped onto it.
+type Config struct {
+	// Preset is the scene preset of the cloth.
+	Preset *Preset
+	// UndoDepth is the maximum number of the undoable edits.
+	UndoDepth int
+	// Snapshots is the number of the cloth snapshots kept in the history,
+	// taken automatically every SnapshotInterval (zero disables them).
+	Snapshots        int
+	SnapshotInterval time.Duration
+	// RenderFPS limits the rendering rate independently of the physics (zero renders every frame).
+	RenderFPS int
+	// PhysicsHz is the fixed rate of the physics steps per second (zero steps once per frame).
+	PhysicsHz float64
+	// IdleAfter stops the physics after the cloth has settled for this long (zero disables it).
+	IdleAfter time.Duration
+	// FrameBudget adapts the sub-steps and the solver iterations to the frame time (zero disables it),
+	// keeping them between the minimum and the maximum.
+	FrameBudget                  time.Duration
+	MinSubSteps, MaxSubSteps     int
+	MinIterations, MaxIterations int
+	// SolverIterations is the initial number of the solver iterations (zero starts from the minimum).
+	SolverIterations int
+	// Deterministic disables the frame time dependent adaptations, so the seed and the inputs reproduce the same run.
+	Deterministic bool
+
+	// DropY is the initial vertical position of the cloth as a fraction of the height (up to 1) or in Dp.
+	DropY float64
+	// Jitter randomly displaces the initial particle positions by this fraction of the spacing.
+	Jitter float64
+	Seed   int64
+	// TensionWarning is the fraction of the tear distance over which the sticks are flashing (zero disables it).
+	TensionWarning float64
+
+	Gravity float64
+	// Wind is the wind toggled with the W key.
+	Wind          WindModel
+	SelfCollision bool
+	// XPBD uses the compliance-based solver instead of the position-based relaxation.
+	XPBD       bool
+	Compliance float64
+	// HemMass is the mass of the particles in the bottom row relative to the others.
+	HemMass      float64
+	DragX, DragY float64
+	Shear, Bend  bool
+	// TearThreshold is the stick length over which the cloth tears. The infinity disables the tearing.
+	TearThreshold float64
+	Plastic       bool
+	Warp, Weft    Grain
+	Floor         Surface
+	Walls         bool
+	Underwater    bool
+	// FieldRadius and FieldStrength are the size and the strength of the charged field around the cursor.
+	FieldRadius, FieldStrength float64
+	// ExplosionTear tears the sticks overstretched by the double click explosion.
+	ExplosionTear bool
+	// Weakening is the falloff mode weakening the sticks by the WeakeningAmount.
+	Weakening       int
+	WeakeningAmount float64
+	Sleep           bool
+	// Springs uses the mass-spring-damper model instead of the position-based constraints.
+	Springs                bool
+	SpringK, SpringDamping float64
+	Friction               float64
+	SlidingPins            bool
+	// StrainLimit is the largest stretch of the sticks relative to their length (zero disables it).
+	StrainLimit float64
+	Obstacles   []Obstacle
+
+	// Palette is the color palette, using its dark scheme if Dark is set.
+	Palette    *Palette
+	Dark       bool
+	Background Background
+	// RenderMode is the coloring of the sticks, where the strain mode colors them by their strain along the Gradient.
+	RenderMode int
+	Gradient   Gradient
+	Fill       bool
+	Texture    image.Image
+	Dots       bool
+	Trails     bool
+	Shadow     bool
+	Fraying    bool
+	Bursts     bool
+	Depth      bool
+	// TensionWidth draws the sticks between the MinLineWidth and the MaxLineWidth by their stretch.
+	TensionWidth               bool
+	MinLineWidth, MaxLineWidth float64
+	// StrokeWidth draws the sticks as anti-aliased strokes, which are slower than the default 1 pixel outlines (zero).
+	StrokeWidth float64
+	LineCap     int
+
+	DebugFrame   bool
+	DebugSolver  bool
+	DebugStrain  bool
+	DebugWinding bool
+}
+
+// DefaultConfig returns the default settings of the cloth widget.
+func DefaultConfig() Config {
+	return Config{
+		Preset:           presets["cloth"],
+		UndoDepth:        defUndoDepth,
+		Snapshots:        10,
+		SnapshotInterval: time.Second,
+		PhysicsHz:        physicsRate,
+		IdleAfter:        5 * time.Second,
+		MinSubSteps:      1,
+		MaxSubSteps:      4,
+		MinIterations:    1,
+		MaxIterations:    8,
+		DropY:            0.2,
+		Seed:             1,
+		TensionWarning:   0.8,
+		Gravity:          gravityForce,
+		Wind:             WindModel{Strength: 400, Gusts: 1, Turbulence: 0.5},
+		Compliance:       1e-6,
+		HemMass:          1,
+		DragX:            0.01,
+		DragY:            0.01,
+		TearThreshold:    stickTearDist,
+		Warp:             Grain{Stiffness: 1, Tear: 1},
+		Weft:             Grain{Stiffness: 1, Tear: 1},
+		Floor:            Surface{Friction: 0.3, Restitution: 0.1},
+		Walls:            true,
+		FieldRadius:      200,
+		FieldStrength:    1e6,
+		ExplosionTear:    true,
+		WeakeningAmount:  0.4,
+		Sleep:            true,
+		SpringK:          1500,
+		SpringDamping:    5,
+		Friction:         0.3,
+		Palette:          palettes["classic"],
+		Gradient:         strainGradient,
+		Fraying:          true,
+		Bursts:           true,
+		MinLineWidth:     0.5,
+		MaxLineWidth:     2.5,
+	}
+}
@@ -0,0 +1,207 @@
+package cloth
+
+import (
+	"image/color"
+	"math"
+
+	"gioui.org/f32"
+	"gioui.org/op/clip"
+)
+
+// flashDecay is the rate the warning flash of the strained sticks fades out from frame to frame.
+const flashDecay = 0.6
+
+// The kinds of the sticks connecting the particles.
+const (
+	// stickStructural is connecting the direct neighbours. Only these sticks are drawn.
+	stickStructural = iota
+	// stickShear is connecting the diagonal neighbours.
+	stickShear
+	// stickBend is connecting the second neighbours.
+	stickBend
+)
+
+// The stiffness of the shear and bending sticks with the position-based solver.
+const (
+	shearStiffness = 0.5
+	bendStiffness  = 0.2
+)
+
+// The parameters of the plastic deformation.
+const (
+	// plasticYield is the stretch ratio over which the sticks get permanently longer.
+	plasticYield = 1.2
+	// plasticRate is the fraction of the stretch over the yield length absorbed by the rest length per iteration.
+	plasticRate = 0.1
+	// plasticMaxStretch limits the rest length of the deformed sticks relative to their initial length.
+	plasticMaxStretch = 2.5
+	// plasticTearScale is raising the tear threshold of the plastic cloth.
+	plasticTearScale = 1.5
+)
+
+// The limits of the fabric grain properties.
+const (
+	minGrainStiffness = 0.05
+	maxGrainStiffness = 2
+	minGrainTear      = 0.1
+)
+
+// The constraint solvers of the simulation.
+const (
+	// solverPBD is the position-based relaxation solver, which stiffness
+	// depends on the time step and the number of iterations.
+	solverPBD = iota
+	// solverXPBD is the compliance-based solver, which stiffness is independent of the time step.
+	solverXPBD
+)
+
+type Constraint struct {
+	p1, p2     *Particle
+	length     float64
+	color      color.NRGBA
+	strain     float64 // the stick length as a fraction of the tear distance
+	flash      float64
+	compliance float64 // the inverse stiffness used by the XPBD solver
+	lambda     float64 // the XPBD Lagrange multiplier accumulated during a step
+	kind       int
+	initial    float64 // the rest length before the plastic deformation
+	stiffness  float64 // the stiffness of the stick relative to the stiffness of its kind
+}
+
+// NewConstraint creates a new constraint between two points/particles.
+// The constraint actually is a stick which connects two points.
+func NewConstraint(p1, p2 *Particle, length float64, col color.NRGBA) *Constraint {
+	return &Constraint{
+		p1: p1, p2: p2, length: length, initial: length, color: col, stiffness: 1,
+	}
+}
+
+// Update updates the stick between two points by resolving the constraints between them.
+func (c *Constraint) Update(cloth *Cloth, mouse *Mouse, delta float64) {
+	dx := c.p1.x - c.p2.x
+	dy := c.p1.y - c.p2.y
+	dz := c.p1.z - c.p2.z
+	dist := distance3(dx, dy, dz)
+	grain := c.grain(cloth)
+	tearDist := cloth.tearDist * grain.Tear * c.stiffness
+	if cloth.plastic {
+		tearDist *= plasticTearScale
+	}
+	c.strain = dist / tearDist
+
+	// The structural sticks are only resisting the stretching, while
+	// the shear and bending sticks are also resisting the compression.
+	if dist < c.length && c.kind == stickStructural || dist == 0 {
+		return
+	}
+	// Tear up the cloth under the mouse position if the applied force exceeds a certain threshold.
+	// The threshold is the distance between the two points.
+	if mouse.tearing() {
+		if dist > tearDist {
+			cloth.tear(c)
+		}
+	}
+	// The plastic sticks are stretched permanently instead of snapping back, making the cloth sag.
+	if cloth.plastic && dist > fround(c.length*plasticYield) {
+		c.length += fround((dist - fround(c.length*plasticYield)) * plasticRate)
+		c.length = math.Min(c.length, c.initial*plasticMaxStretch)
+	}
+
+	if cloth.solver == solverXPBD {
+		c.solveXPBD(dx, dy, dz, dist, delta, grain.Stiffness*c.stiffness)
+		return
+	}
+
+	diff := (c.length - dist) / dist
+	mul := fround(diff*0.4*grain.Stiffness*c.stiffness) * (1 - c.length/dist)
+	switch c.kind {
+	case stickShear:
+		mul = fround(diff * 0.5 * shearStiffness * c.stiffness)
+	case stickBend:
+		mul = fround(diff * 0.5 * bendStiffness * c.stiffness)
+	}
+
+	offsetX, offsetY, offsetZ := fround(dx*mul), fround(dy*mul), fround(dz*mul)
+
+	// The correction is distributed between the two particles inversely proportional to their masses.
+	// Particles of equal mass are moved by the same amount, while a pinned particle doesn't move at all.
+	w1, w2 := 1.0, 1.0
+	if !c.p1.pinX && !c.p2.pinX {
+		im1, im2 := c.p1.invMass(), c.p2.invMass()
+		w1, w2 = 2*im1/(im1+im2), 2*im2/(im1+im2)
+	}
+	if !c.p1.pinX {
+		c.p1.x += fround(offsetX * w1)
+		c.p1.y += fround(offsetY * w1)
+		c.p1.z += fround(offsetZ * w1)
+	}
+	if !c.p2.pinX {
+		c.p2.x -= fround(offsetX * w2)
+		c.p2.y -= fround(offsetY * w2)
+		c.p2.z -= fround(offsetZ * w2)
+	}
+}
+
+// grain returns the material properties along the direction of the stick. The vertical structural
+// sticks are following the warp, the horizontal ones the weft of the fabric.
+func (c *Constraint) grain(cloth *Cloth) Grain {
+	switch {
+	case c.kind != stickStructural:
+		return Grain{Stiffness: 1, Tear: 1}
+	case c.p1.col == c.p2.col:
+		return cloth.warp
+	default:
+		return cloth.weft
+	}
+}
+
+// solveXPBD corrects the stick end points using the XPBD solver, where the stretching
+// is resisted according to the compliance of the stick scaled by the time step.
+func (c *Constraint) solveXPBD(dx, dy, dz, dist, delta, stiffness float64) {
+	w1, w2 := c.p1.invMass(), c.p2.invMass()
+	alpha := c.compliance / stiffness / fround(delta*delta)
+	if w1+w2+alpha == 0 {
+		return
+	}
+	// The constraint is C = dist - length, which gradient is the unit vector pointing from p2 to p1.
+	dlambda := (c.length - dist - fround(alpha*c.lambda)) / (w1 + w2 + alpha)
+	c.lambda += dlambda
+
+	nx, ny, nz := dx/dist, dy/dist, dz/dist
+	c.p1.x += fround(w1 * dlambda * nx)
+	c.p1.y += fround(w1 * dlambda * ny)
+	c.p1.z += fround(w1 * dlambda * nz)
+	c.p2.x -= fround(w2 * dlambda * nx)
+	c.p2.y -= fround(w2 * dlambda * ny)
+	c.p2.z -= fround(w2 * dlambda * nz)
+}
+
+// addPath adds the stick outline to the path, where the stick end points
+// are interpolated between the last two simulation steps by `alpha`.
+func (c *Constraint) addPath(path *clip.Path, alpha float64) {
+	x1, y1 := c.p1.position(alpha)
+	x2, y2 := c.p2.position(alpha)
+
+	// We are using `clip.Outline` instead of `clip.Stroke` for performance reasons.
+	// But we need to draw the full outline of the stroke.
+	path.MoveTo(f32.Pt(float32(x1), float32(y1)))
+	path.LineTo(f32.Pt(float32(x2), float32(y2)))
+	path.LineTo(f32.Pt(float32(x2+1), float32(y2)))
+	path.LineTo(f32.Pt(float32(x1+1), float32(y1)))
+
+	path.MoveTo(f32.Pt(float32(x1), float32(y1)))
+	path.LineTo(f32.Pt(float32(x2), float32(y2)))
+	path.LineTo(f32.Pt(float32(x2), float32(y2+1)))
+	path.LineTo(f32.Pt(float32(x1), float32(y1+1)))
+	path.Close()
+}
+
+// removeConstraint removes a specific constraint (stick) from the collection, stored into a slice.
+func (c *Constraint) removeConstraint(cloth *Cloth) {
+	for idx, constraint := range cloth.constraints {
+		if c == constraint {
+			cloth.constraints = append(cloth.constraints[:idx], cloth.constraints[idx+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package cloth
+
+import "math"
+
+// cut severs every stick crossing the segment between the {x0, y0} and {x1, y1} points.
+func (c *Cloth) cut(x0, y0, x1, y1 float64) {
+	if x0 == x1 && y0 == y1 {
+		return
+	}
+	var cuts []*Constraint
+	for _, ct := range c.constraints {
+		if ct.p1.isActive && crosses(x0, y0, x1, y1, ct.p1.x, ct.p1.y, ct.p2.x, ct.p2.y) {
+			cuts = append(cuts, ct)
+		}
+	}
+	for _, ct := range cuts {
+		c.applyEdit(&removeEdit{c: ct})
+	}
+}
+
+// segmentDistance returns the distance of the {x, y} point from the segment between the {x0, y0} and {x1, y1} points.
+func segmentDistance(x, y, x0, y0, x1, y1 float64) float64 {
+	sx, sy := x1-x0, y1-y0
+	t := 0.0
+	if l := fround(sx*sx) + fround(sy*sy); l > 0 {
+		t = math.Max(0, math.Min((fround((x-x0)*sx)+fround((y-y0)*sy))/l, 1))
+	}
+	return distance(x-x0-fround(t*sx), y-y0-fround(t*sy))
+}
+
+// crosses reports whether the segment between the {ax, ay} and {bx, by} points
+// intersects the segment between the {cx, cy} and {dx, dy} points.
+func crosses(ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	// The end points of each segment must lie on the opposite sides of the other segment.
+	side := func(px, py, qx, qy, rx, ry float64) float64 {
+		return fround((qx-px)*(ry-py)) - fround((qy-py)*(rx-px))
+	}
+	d1, d2 := side(cx, cy, dx, dy, ax, ay), side(cx, cy, dx, dy, bx, by)
+	d3, d4 := side(ax, ay, bx, by, cx, cy), side(ax, ay, bx, by, dx, dy)
+	return (d1 > 0) != (d2 > 0) && (d3 > 0) != (d4 > 0)
+}
@@ -0,0 +1,192 @@
+package cloth
+
+import (
+	"image"
+	"math"
+	"sort"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+const (
+	// depthWind is the part of the wind blowing into the depth of the 3D cloth, as a fraction of its strength.
+	depthWind = 0.5
+	// focalLength is the distance of the eye from the plane of the flat cloth in pixels.
+	focalLength = 900
+	// orbitStep is the angle the camera orbits around the 3D cloth by a key press.
+	orbitStep = math.Pi / 24
+	// minDepthShade is the lightness of the cells seen edge-on, relative to the cells facing the camera.
+	minDepthShade = 0.35
+)
+
+// SetDepth turns on or off the 3D simulation, where the wind also blows the cloth into the depth,
+// and the cloth is drawn in perspective, viewed by the camera orbiting around it.
+// Turning it off flattens the cloth back into the plane.
+func (c *Cloth) SetDepth(on bool) {
+	c.depth = on
+	if !on {
+		for _, p := range c.particles {
+			p.z, p.pz, p.lz = 0, 0, 0
+		}
+		c.yaw = 0
+	}
+}
+
+// Depth reports whether the cloth is simulated in 3D.
+func (c *Cloth) Depth() bool {
+	return c.depth
+}
+
+// Orbit orbits the camera around the vertical axis of the 3D cloth by the angle in radians.
+func (c *Cloth) Orbit(angle float64) {
+	c.yaw = math.Remainder(c.yaw+angle, 2*math.Pi)
+}
+
+// windDepthAt returns the wind acceleration acting along the depth on a particle at the {x, y} position.
+func (c *Cloth) windDepthAt(x, y float64) float64 {
+	m := c.windModel
+	if !m.Enabled || m.Strength == 0 {
+		return 0
+	}
+	return fround(c.gustStrength()*depthWind) + c.turbulenceAt(x, y, 12.7, 45.3)
+}
+
+// projector projects the 3D positions onto the screen, rotated by the camera yaw around the vertical axis through the center.
+type projector struct {
+	cx, cy   float64
+	sin, cos float64
+}
+
+// projector returns the projector of the perspective view of the `size` area.
+func (c *Cloth) projector(size image.Point) projector {
+	return projector{cx: float64(size.X) / 2, cy: float64(size.Y) / 2, sin: math.Sin(c.yaw), cos: math.Cos(c.yaw)}
+}
+
+// project returns the screen position of the {x, y, z} position and its distance from the eye along the view.
+func (pr projector) project(x, y, z float64) (f32.Point, float64) {
+	x -= pr.cx
+	rx := x*pr.cos - z*pr.sin
+	rz := x*pr.sin + z*pr.cos
+	scale := focalLength / (focalLength + rz)
+	return f32.Pt(float32(pr.cx+rx*scale), float32(pr.cy+(y-pr.cy)*scale)), rz
+}
+
+// unproject returns the position on the plane of the flat cloth, which is projected to the {x, y} position.
+// It reports false if the plane isn't seen there, e.g. when the cloth is viewed edge-on.
+func (pr projector) unproject(x, y float64) (f32.Point, bool) {
+	x -= pr.cx
+	d := focalLength*pr.cos - x*pr.sin
+	if d <= 0 {
+		return f32.Point{}, false
+	}
+	x = x * focalLength / d
+	scale := focalLength / (focalLength + x*pr.sin)
+	return f32.Pt(float32(pr.cx+x), float32(pr.cy+(y-pr.cy)/scale)), true
+}
+
+// unproject maps the pointer position on the perspective view of the `size` area onto the plane of the flat cloth,
+// so the pointer tools are acting on the cloth seen under the pointer after the camera has orbited.
+// The tools are acting on the flat position, so the pointer can be slightly off the cloth bulging into the depth.
+func (c *Cloth) unproject(pt f32.Point, size image.Point) f32.Point {
+	if !c.depth || c.yaw == 0 {
+		return pt
+	}
+	if p, ok := c.projector(size).unproject(float64(pt.X), float64(pt.Y)); ok {
+		return p
+	}
+	return pt
+}
+
+// rotate returns the {x, y, z} vector rotated by the camera yaw.
+func (pr projector) rotate(x, y, z float64) (rx, ry, rz float64) {
+	return x*pr.cos - z*pr.sin, y, x*pr.sin + z*pr.cos
+}
+
+// draw3D draws the cloth in perspective. The cells are sorted from the farthest to the nearest,
+// so the nearer cells are painted over the farther ones, and shaded by how much they face the camera.
+// The presets without a grid are drawn as projected sticks.
+func (c *Cloth) draw3D(gtx layout.Context, alpha float64) {
+	pr := c.projector(gtx.Constraints.Max)
+
+	quads := c.quads()
+	if len(quads) == 0 {
+		var path clip.Path
+		path.Begin(gtx.Ops)
+		for _, ct := range c.constraints {
+			if !ct.p1.isActive || ct.kind != stickStructural {
+				continue
+			}
+			x1, y1 := ct.p1.position(alpha)
+			x2, y2 := ct.p2.position(alpha)
+			p1, _ := pr.project(x1, y1, ct.p1.depth(alpha))
+			p2, _ := pr.project(x2, y2, ct.p2.depth(alpha))
+			path.MoveTo(p1)
+			path.LineTo(p2)
+		}
+		paint.FillShape(gtx.Ops, c.color, clip.Stroke{Path: path.End(), Width: 1}.Op())
+		return
+	}
+
+	type face struct {
+		pts   [4]f32.Point
+		depth float64
+		shade int
+	}
+	faces := make([]face, len(quads))
+	for i, q := range quads {
+		var pos [4][3]float64
+		f := &faces[i]
+		for j, p := range q {
+			x, y := p.position(alpha)
+			z := p.depth(alpha)
+			pos[j] = [3]float64{x, y, z}
+			var d float64
+			f.pts[j], d = pr.project(x, y, z)
+			f.depth += d / 4
+		}
+		// The normal is the cross product of the diagonals, and the light is coming from the camera.
+		ax, ay, az := pr.rotate(pos[2][0]-pos[0][0], pos[2][1]-pos[0][1], pos[2][2]-pos[0][2])
+		bx, by, bz := pr.rotate(pos[3][0]-pos[1][0], pos[3][1]-pos[1][1], pos[3][2]-pos[1][2])
+		nx, ny, nz := ay*bz-az*by, az*bx-ax*bz, ax*by-ay*bx
+		facing := 0.0
+		if n := distance3(nx, ny, nz); n > 0 {
+			facing = math.Abs(nz) / n
+		}
+		f.shade = int(math.Round(facing * (fillShades - 1)))
+	}
+	sort.SliceStable(faces, func(i, j int) bool { return faces[i].depth > faces[j].depth })
+
+	var cols [fillShades]paint.ColorOp
+	for shade := range cols {
+		light := minDepthShade + (1-minDepthShade)*float64(shade)/(fillShades-1)
+		col := c.color
+		col.R, col.G, col.B = uint8(float64(col.R)*light), uint8(float64(col.G)*light), uint8(float64(col.B)*light)
+		cols[shade] = paint.ColorOp{Color: col}
+	}
+	// The consecutive cells of the same shade are drawn as a single clip path, keeping the painting order.
+	for start := 0; start < len(faces); {
+		end := start
+		var path clip.Path
+		path.Begin(gtx.Ops)
+		for ; end < len(faces) && faces[end].shade == faces[start].shade; end++ {
+			pts := faces[end].pts
+			// The cells seen from behind are reversed, so they don't cancel out the overlapping cells seen from the front.
+			if (pts[1].X-pts[0].X)*(pts[3].Y-pts[0].Y)-(pts[1].Y-pts[0].Y)*(pts[3].X-pts[0].X) < 0 {
+				pts[1], pts[3] = pts[3], pts[1]
+			}
+			path.MoveTo(pts[0])
+			path.LineTo(pts[1])
+			path.LineTo(pts[2])
+			path.LineTo(pts[3])
+			path.Close()
+		}
+		area := clip.Outline{Path: path.End()}.Op().Push(gtx.Ops)
+		cols[faces[start].shade].Add(gtx.Ops)
+		paint.PaintOp{}.Add(gtx.Ops)
+		area.Pop()
+		start = end
+	}
+}
@@ -0,0 +1,59 @@
+package cloth
+
+import (
+	"image/color"
+	"math"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+const (
+	// dotRadius is the radius of the dots drawn at the particle positions.
+	dotRadius = 2.5
+	// pinDotRadius is the radius of the dots drawn at the pinned particles.
+	pinDotRadius = 4
+)
+
+// pinDotColor is the color of the dots drawn at the pinned particles.
+var pinDotColor = color.NRGBA{R: 0xe0, G: 0x40, B: 0x30, A: 0xff}
+
+// SetDots turns on or off drawing a dot at every particle, which makes the discrete structure of the cloth visible.
+func (c *Cloth) SetDots(on bool) {
+	c.dots = on
+}
+
+// Dots reports whether a dot is drawn at every particle.
+func (c *Cloth) Dots() bool {
+	return c.dots
+}
+
+// drawDots draws a dot at every active particle, and a larger one in a different color at the pinned particles.
+// Similarly to the sticks, the dots of the same color are drawn as a single clip path.
+func (c *Cloth) drawDots(gtx layout.Context, alpha float64) {
+	var free, pinned clip.Path
+	free.Begin(gtx.Ops)
+	pinned.Begin(gtx.Ops)
+	for _, p := range c.particles {
+		if !p.isActive {
+			continue
+		}
+		x, y := p.position(alpha)
+		if p.pinX || p.rail {
+			addDot(&pinned, float32(x), float32(y), pinDotRadius)
+		} else {
+			addDot(&free, float32(x), float32(y), dotRadius)
+		}
+	}
+	paint.FillShape(gtx.Ops, c.color, clip.Outline{Path: free.End()}.Op())
+	paint.FillShape(gtx.Ops, pinDotColor, clip.Outline{Path: pinned.End()}.Op())
+}
+
+// addDot adds a circle with the center {x, y} and the radius `r` to the path.
+func addDot(path *clip.Path, x, y, r float32) {
+	path.MoveTo(f32.Pt(x-r, y))
+	path.Arc(f32.Pt(r, 0), f32.Pt(r, 0), 2*math.Pi)
+	path.Close()
+}
@@ -0,0 +1,114 @@
+package cloth
+
+// Edit is a reversible modification of the cloth topology or of the particles pin state.
+// Every destructive tool should alter the cloth through an Edit, so that it can be undone.
+// The continuous physics motion is not undoable, only the structural changes are.
+type Edit interface {
+	Apply(cloth *Cloth)
+	Revert(cloth *Cloth)
+}
+
+// tearEdit deactivates a particle, making a hole in the cloth structure.
+type tearEdit struct {
+	p *Particle
+}
+
+func (e *tearEdit) Apply(cloth *Cloth)  { e.p.isActive = false }
+func (e *tearEdit) Revert(cloth *Cloth) { e.p.isActive = true }
+
+// pinEdit pins up or releases a particle.
+type pinEdit struct {
+	p      *Particle
+	pinned bool
+}
+
+func (e *pinEdit) Apply(cloth *Cloth)  { e.p.pin(e.pinned) }
+func (e *pinEdit) Revert(cloth *Cloth) { e.p.pin(!e.pinned) }
+
+// removeEdit removes a constraint (stick) from the cloth.
+type removeEdit struct {
+	c *Constraint
+}
+
+func (e *removeEdit) Apply(cloth *Cloth) { e.c.removeConstraint(cloth) }
+func (e *removeEdit) Revert(cloth *Cloth) {
+	cloth.constraints = append(cloth.constraints, e.c)
+}
+
+// batchEdit groups together the edits made during a single mouse gesture.
+type batchEdit []Edit
+
+func (b batchEdit) Apply(cloth *Cloth) {
+	for _, e := range b {
+		e.Apply(cloth)
+	}
+}
+
+func (b batchEdit) Revert(cloth *Cloth) {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i].Revert(cloth)
+	}
+}
+
+// History is a bounded undo/redo stack of the cloth edits.
+type History struct {
+	depth   int
+	undo    []Edit
+	redo    []Edit
+	pending batchEdit
+}
+
+// NewHistory creates a new undo/redo stack holding at most `depth` edits.
+func NewHistory(depth int) *History {
+	return &History{depth: depth}
+}
+
+// Record stores an already applied edit into the pending batch.
+// The batch is pushed onto the undo stack when it gets committed.
+func (h *History) Record(e Edit) {
+	h.pending = append(h.pending, e)
+}
+
+// Commit closes the pending batch and pushes it onto the undo stack.
+// A new edit invalidates the redo stack.
+func (h *History) Commit() {
+	if len(h.pending) == 0 {
+		return
+	}
+	h.undo = append(h.undo, h.pending)
+	if h.depth > 0 && len(h.undo) > h.depth {
+		h.undo = h.undo[len(h.undo)-h.depth:]
+	}
+	h.redo = nil
+	h.pending = nil
+}
+
+// Undo reverts the last committed edit.
+func (h *History) Undo(cloth *Cloth) {
+	h.Commit()
+	if len(h.undo) == 0 {
+		return
+	}
+	e := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	e.Revert(cloth)
+	cloth.wake()
+	h.redo = append(h.redo, e)
+}
+
+// Redo reapplies the last reverted edit.
+func (h *History) Redo(cloth *Cloth) {
+	if len(h.redo) == 0 {
+		return
+	}
+	e := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	e.Apply(cloth)
+	cloth.wake()
+	h.undo = append(h.undo, e)
+}
+
+// Clear empties both the undo and the redo stack.
+func (h *History) Clear() {
+	h.undo, h.redo, h.pending = nil, nil, nil
+}
@@ -0,0 +1,51 @@
+package cloth
+
+import "time"
+
+const (
+	// explosionRadius is the radius of the explosion triggered by a double click.
+	explosionRadius = 120
+	// explosionImpulse is the displacement of the particles at the center of the explosion.
+	explosionImpulse = 40
+	// explosionStretch is the stretch ratio over which the sticks are torn by the explosion.
+	explosionStretch = 2.5
+	// doubleClickTime and doubleClickDist are the maximum time and distance between two clicks of a double click.
+	doubleClickTime = 300 * time.Millisecond
+	doubleClickDist = 10
+)
+
+// Explode pushes away the particles around the {x, y} position with a radial impulse,
+// which is fading out with the distance up to the radius. With `tear` set the sticks
+// overstretched by the impulse are torn, blowing a hole into the cloth.
+func (c *Cloth) Explode(x, y, radius, impulse float64, tear bool) {
+	for _, p := range c.particles {
+		if !p.isActive || p.pinX {
+			continue
+		}
+		dx, dy := p.x-x, p.y-y
+		dist := distance(dx, dy)
+		if dist >= radius {
+			continue
+		}
+		if dist == 0 {
+			dx, dy, dist = 0, -1, 1
+		}
+		// Moving the particle without its previous position also gives it the velocity of the impulse.
+		push := impulse * (1 - dist/radius) / dist
+		p.x += fround(dx * push)
+		p.y += fround(dy * push)
+	}
+	if !tear {
+		return
+	}
+	var torn []*Constraint
+	for _, ct := range c.constraints {
+		if distance(ct.p1.x-ct.p2.x, ct.p1.y-ct.p2.y) > ct.length*explosionStretch {
+			torn = append(torn, ct)
+		}
+	}
+	for _, ct := range torn {
+		c.tear(ct)
+	}
+	c.history.Commit()
+}
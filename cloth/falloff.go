@@ -0,0 +1,53 @@
+package cloth
+
+import "fmt"
+
+const (
+	// falloffNone keeps the same stiffness for every stick.
+	falloffNone = iota
+	// falloffBottom weakens the sticks linearly toward the bottom of the cloth.
+	falloffBottom
+	// falloffNoise weakens the sticks following a Perlin noise field, making a few weak spots.
+	falloffNoise
+)
+
+// falloffModes maps the names of the falloff modes to their values.
+var falloffModes = map[string]int{
+	"none":   falloffNone,
+	"bottom": falloffBottom,
+	"noise":  falloffNoise,
+}
+
+// LookupFalloff returns the falloff mode with the given name.
+func LookupFalloff(name string) (int, error) {
+	mode, ok := falloffModes[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown weakening %q (available: none, bottom, noise)", name)
+	}
+	return mode, nil
+}
+
+// falloffScale is the frequency of the noise field weakening the sticks, per grid cell.
+const falloffScale = 0.15
+
+// SetFalloff sets how the sticks created by Init are weakened. The stiffness and the tear threshold
+// of the weakest sticks are reduced by the `amount` fraction, so the cloth starts tearing at the weak places.
+func (c *Cloth) SetFalloff(mode int, amount float64) {
+	c.falloff, c.falloffAmount = mode, amount
+}
+
+// stickStiffness returns the stiffness of a new stick between the p1 and p2 particles.
+func (c *Cloth) stickStiffness(p1, p2 *Particle) float64 {
+	col := float64(p1.col+p2.col) / 2
+	row := float64(p1.row+p2.row) / 2
+	switch c.falloff {
+	case falloffBottom:
+		if rows := c.Rows() - 1; rows > 0 {
+			return 1 - fround(c.falloffAmount*row)/float64(rows)
+		}
+	case falloffNoise:
+		n := (c.noise.At(col*falloffScale, row*falloffScale, 0) + 1) / 2
+		return 1 - fround(c.falloffAmount*n)
+	}
+	return 1
+}
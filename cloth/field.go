@@ -0,0 +1,29 @@
+package cloth
+
+import "math"
+
+// fieldMinDist limits the inverse-square force of the charged field close to the cursor.
+const fieldMinDist = 10
+
+// fieldAt returns the acceleration of the charged field around the cursor acting on a particle
+// at the {x, y} position. The field attracts or repels the particles with an inverse-square falloff,
+// fading out smoothly at the field radius.
+func (c *Cloth) fieldAt(mouse *Mouse, x, y float64) (ax, ay float64) {
+	if mouse.field == 0 || c.fieldRadius <= 0 {
+		return 0, 0
+	}
+	dx, dy := mouse.x-x, mouse.y-y
+	dist := distance(dx, dy)
+	if dist >= c.fieldRadius || dist == 0 {
+		return 0, 0
+	}
+	fade := 1 - dist/c.fieldRadius
+	d := math.Max(dist, fieldMinDist)
+	a := fround(float64(mouse.field) * c.fieldCharge / (d * d) * fade * fade)
+	return fround(dx / dist * a), fround(dy / dist * a)
+}
+
+// SetField sets the radius and the strength of the charged field around the cursor.
+func (c *Cloth) SetField(radius, charge float64) {
+	c.fieldRadius, c.fieldCharge = radius, charge
+}
@@ -0,0 +1,179 @@
+package cloth
+
+import (
+	"image/color"
+	"math"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+const (
+	// fillShades is the number of the shades of the filled cloth. The cells of the same shade
+	// are drawn as a single clip path, the same way as the sticks.
+	fillShades = 8
+	// fillContrast is the largest lightening or darkening of the shaded cells.
+	fillContrast = 0.35
+	// minFillStretch and maxFillStretch are the area ratios of the darkest and the lightest cells.
+	minFillStretch = 0.25
+	maxFillStretch = 1.75
+	// windingDot is the radius of the dots marking the cells which are not flipped.
+	windingDot = 1
+)
+
+var (
+	// windingColor is the color of the dots marking the cells which are not flipped.
+	windingColor = color.NRGBA{R: 0x20, G: 0xa0, B: 0x40, A: 0xff}
+	// flippedColor and flippedEdge are the fill and the cross color of the flipped cells.
+	flippedColor = color.NRGBA{R: 0xe0, G: 0x30, B: 0x20, A: 0x80}
+	flippedEdge  = color.NRGBA{R: 0xa0, G: 0x10, B: 0x10, A: 0xff}
+)
+
+// quad is a cell of the cloth grid given by its corner particles, in the order of drawing.
+type quad [4]*Particle
+
+// SetFill switches between drawing the cloth as a net of sticks and filling its cells.
+func (c *Cloth) SetFill(fill bool) {
+	c.fill = fill
+}
+
+// Fill reports whether the cells of the cloth are filled.
+func (c *Cloth) Fill() bool {
+	return c.fill
+}
+
+// quads returns the intact cells of the cloth grid, which are still bounded by all four structural sticks.
+func (c *Cloth) quads() []quad {
+	type cell struct{ sheet, col, row int }
+	grid := make(map[cell]*Particle, len(c.particles))
+	for _, p := range c.particles {
+		if p.isActive && p.col >= 0 {
+			grid[cell{p.sheet, p.col, p.row}] = p
+		}
+	}
+	sticks := make(map[[2]*Particle]bool, len(c.constraints))
+	for _, ct := range c.constraints {
+		if ct.kind == stickStructural {
+			sticks[[2]*Particle{ct.p1, ct.p2}] = true
+			sticks[[2]*Particle{ct.p2, ct.p1}] = true
+		}
+	}
+
+	var quads []quad
+	for _, p := range c.particles {
+		if !p.isActive || p.col < 0 {
+			continue
+		}
+		right := grid[cell{p.sheet, p.col + 1, p.row}]
+		down := grid[cell{p.sheet, p.col, p.row + 1}]
+		diag := grid[cell{p.sheet, p.col + 1, p.row + 1}]
+		if right == nil || down == nil || diag == nil {
+			continue
+		}
+		if sticks[[2]*Particle{p, right}] && sticks[[2]*Particle{p, down}] &&
+			sticks[[2]*Particle{right, diag}] && sticks[[2]*Particle{down, diag}] {
+			quads = append(quads, quad{p, right, diag, down})
+		}
+	}
+	return quads
+}
+
+// drawFill fills the cells of the cloth as two triangles each. The cells are shaded by their area
+// relative to the rest area, so the stretched parts of the cloth are lighter and the folds are darker.
+// It reports whether the cloth has any cells to fill.
+func (c *Cloth) drawFill(gtx layout.Context, alpha float64) bool {
+	quads := c.quads()
+	if len(quads) == 0 {
+		return false
+	}
+	var shades [fillShades]clip.Path
+	var used [fillShades]bool
+	rest := float64(c.spacing * c.spacing)
+	for _, q := range quads {
+		var pts [4]f32.Point
+		for i, p := range q {
+			x, y := p.position(alpha)
+			pts[i] = f32.Pt(float32(x), float32(y))
+		}
+		// The area of the quad is calculated with the shoelace formula.
+		var area float32
+		for i := range pts {
+			j := (i + 1) % len(pts)
+			area += pts[i].X*pts[j].Y - pts[j].X*pts[i].Y
+		}
+		stretch := math.Max(minFillStretch, math.Min(math.Abs(float64(area))/2/rest, maxFillStretch))
+		shade := int(math.Round((stretch - minFillStretch) / (maxFillStretch - minFillStretch) * (fillShades - 1)))
+
+		path := &shades[shade]
+		if !used[shade] {
+			path.Begin(gtx.Ops)
+			used[shade] = true
+		}
+		path.MoveTo(pts[0])
+		path.LineTo(pts[1])
+		path.LineTo(pts[2])
+		path.Close()
+		path.MoveTo(pts[0])
+		path.LineTo(pts[2])
+		path.LineTo(pts[3])
+		path.Close()
+	}
+
+	base := LinearFromSRGB(c.color).HSLA()
+	for shade := range shades {
+		if !used[shade] {
+			continue
+		}
+		col := base
+		if s := float32(shade)/(fillShades-1)*2 - 1; s > 0 {
+			col = col.Lighten(s * fillContrast)
+		} else {
+			col = col.Darken(-s * fillContrast)
+		}
+		paint.FillShape(gtx.Ops, col.RGBA().SRGB(), clip.Outline{Path: shades[shade].End()}.Op())
+	}
+	return true
+}
+
+// drawWinding draws the winding of every cell over the cloth, for diagnosing the cells flipped by folding
+// the cloth over itself. The cells wound the same way as at rest are marked by a dot in their center,
+// while the flipped cells are filled with the warning color and crossed out.
+func (c *Cloth) drawWinding(gtx layout.Context, alpha float64) {
+	var dots, flipped, crosses clip.Path
+	dots.Begin(gtx.Ops)
+	flipped.Begin(gtx.Ops)
+	crosses.Begin(gtx.Ops)
+	for _, q := range c.quads() {
+		var pts [4]f32.Point
+		var center f32.Point
+		for i, p := range q {
+			x, y := p.position(alpha)
+			pts[i] = f32.Pt(float32(x), float32(y))
+			center = center.Add(pts[i].Mul(0.25))
+		}
+		var area float32
+		for i := range pts {
+			j := (i + 1) % len(pts)
+			area += pts[i].X*pts[j].Y - pts[j].X*pts[i].Y
+		}
+		// The cells are wound clockwise on the screen at rest, which is a positive area with the y axis pointing down.
+		if area >= 0 {
+			addDot(&dots, center.X, center.Y, windingDot)
+			continue
+		}
+		flipped.MoveTo(pts[0])
+		for _, pt := range pts[1:] {
+			flipped.LineTo(pt)
+		}
+		flipped.Close()
+		crosses.MoveTo(pts[0])
+		crosses.LineTo(pts[2])
+		crosses.MoveTo(pts[1])
+		crosses.LineTo(pts[3])
+	}
+	paint.FillShape(gtx.Ops, windingColor, clip.Outline{Path: dots.End()}.Op())
+	paint.FillShape(gtx.Ops, flippedColor, clip.Outline{Path: flipped.End()}.Op())
+	paint.FillShape(gtx.Ops, flippedEdge, clip.Stroke{Path: crosses.End(), Width: 1}.Op())
+}
@@ -0,0 +1,32 @@
+//go:build !strictfp
+
+package cloth
+
+import "math"
+
+// fround returns the result of a floating point operation, letting the compiler
+// fuse it with the neighbouring operations (e.g. FMA on arm64) for performance.
+// Build with the `strictfp` tag to get bit-identical results across platforms.
+func fround(x float64) float64 {
+	return x
+}
+
+// distance returns the length of the {dx, dy} vector.
+func distance(dx, dy float64) float64 {
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
+// distance3 returns the length of the {dx, dy, dz} vector.
+func distance3(dx, dy, dz float64) float64 {
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// sin returns the sine of x.
+func sin(x float64) float64 {
+	return math.Sin(x)
+}
+
+// cos returns the cosine of x.
+func cos(x float64) float64 {
+	return math.Cos(x)
+}
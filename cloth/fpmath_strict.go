@@ -0,0 +1,118 @@
+//go:build strictfp
+
+package cloth
+
+import "math"
+
+// fround forces the rounding of a floating point operation to float64 precision.
+// An explicit conversion prevents the compiler from fusing the operation with
+// the neighbouring ones (e.g. FMA on arm64), which would give slightly different
+// results on different architectures.
+func fround(x float64) float64 {
+	return float64(x)
+}
+
+// distance returns the length of the {dx, dy} vector. The square root is
+// correctly rounded by IEEE 754 on every platform, only the sum needs rounding.
+func distance(dx, dy float64) float64 {
+	return math.Sqrt(fround(dx*dx) + fround(dy*dy))
+}
+
+// distance3 returns the length of the {dx, dy, dz} vector.
+func distance3(dx, dy, dz float64) float64 {
+	return math.Sqrt(fround(dx*dx) + fround(dy*dy) + fround(dz*dz))
+}
+
+// The coefficients of the sine and cosine polynomials and Pi/4 split into three parts,
+// the same ones as used by the math package.
+var (
+	sinCoef = [...]float64{
+		1.58962301576546568060e-10,
+		-2.50507477628578072866e-8,
+		2.75573136213857245213e-6,
+		-1.98412698295895385996e-4,
+		8.33333333332211858878e-3,
+		-1.66666666666666307295e-1,
+	}
+	cosCoef = [...]float64{
+		-1.13585365213876817300e-11,
+		2.08757008419747316778e-9,
+		-2.75573141792967388112e-7,
+		2.48015872888517045348e-5,
+		-1.38888888888730564116e-3,
+		4.16666666666665929218e-2,
+	}
+)
+
+const (
+	pi4A = 7.85398125648498535156e-1
+	pi4B = 3.77489470793079817668e-8
+	pi4C = 2.69515142907905952645e-15
+	// maxTrigArg is the largest argument reduced precisely by the three parts of Pi/4.
+	maxTrigArg = 1 << 29
+)
+
+// sin returns the sine of x. The math package evaluates the same polynomial, but its operations
+// might be fused on some architectures, so it's evaluated here with every operation rounded.
+func sin(x float64) float64 {
+	return trig(x, false)
+}
+
+// cos returns the cosine of x, evaluated the same way as sin.
+func cos(x float64) float64 {
+	return trig(x, true)
+}
+
+// trig returns the sine, or the cosine if `cosine` is set, of x. The arguments over maxTrigArg
+// are left to the math package, the simulated time and the angles are never that large.
+func trig(x float64, cosine bool) float64 {
+	switch {
+	case math.IsNaN(x) || math.IsInf(x, 0):
+		return math.NaN()
+	case math.Abs(x) >= maxTrigArg && cosine:
+		return math.Cos(x)
+	case math.Abs(x) >= maxTrigArg:
+		return math.Sin(x)
+	}
+	// The sine is odd and the cosine is even.
+	sign := false
+	if x < 0 {
+		x, sign = -x, !cosine
+	}
+	// The argument is reduced to the [-Pi/4, Pi/4] range by the octant j.
+	j := uint64(x * (4 / math.Pi))
+	y := float64(j)
+	if j&1 == 1 {
+		j++
+		y++
+	}
+	j &= 7
+	z := x - fround(y*pi4A) - fround(y*pi4B) - fround(y*pi4C)
+	if j > 3 {
+		j -= 4
+		sign = !sign
+	}
+	if cosine && j > 1 {
+		sign = !sign
+	}
+	zz := fround(z * z)
+	// The sine and the cosine are swapping in the odd octants.
+	if (j == 1 || j == 2) != cosine {
+		y = 1 - fround(0.5*zz) + fround(fround(zz*zz)*horner(zz, cosCoef))
+	} else {
+		y = z + fround(fround(z*zz)*horner(zz, sinCoef))
+	}
+	if sign {
+		y = -y
+	}
+	return y
+}
+
+// horner evaluates the polynomial of the coefficients at x with every operation rounded.
+func horner(x float64, coef [6]float64) float64 {
+	y := coef[0]
+	for _, c := range coef[1:] {
+		y = fround(y*x) + c
+	}
+	return y
+}
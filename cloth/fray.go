@@ -0,0 +1,112 @@
+package cloth
+
+import (
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+const (
+	// fraySegments is the number of the segments of a dangling thread, and fraySegment is their length.
+	fraySegments = 3
+	fraySegment  = 2.5
+	// frayLife is the time in seconds the threads are dangling before they vanish.
+	frayLife = 4.0
+	// frayDrag is the fraction of the thread velocity lost in every step.
+	frayDrag = 0.05
+	// maxFrays limits the number of the dangling threads. The oldest ones are dropped first.
+	maxFrays = 500
+)
+
+// thread is a short stub of a torn stick dangling from the particle it has been attached to.
+// It's a visual effect, which is simulated separately from the cloth and it's not part of its state.
+type thread struct {
+	anchor *Particle
+	pts    [fraySegments + 1]f32.Point // the points of the thread, starting at the anchor
+	prev   [fraySegments + 1]f32.Point // the points of the thread in the previous step
+	age    float64
+}
+
+// SetFraying turns on or off leaving the dangling threads at the ends of the torn sticks.
+func (c *Cloth) SetFraying(on bool) {
+	c.fraying = on
+	c.frays = nil
+}
+
+// tear removes the torn stick from the cloth, bursting out sparks and leaving a dangling thread at both of its ends.
+func (c *Cloth) tear(ct *Constraint) {
+	c.applyEdit(&removeEdit{c: ct})
+	if c.burst != nil {
+		c.burst.Emit((ct.p1.x+ct.p2.x)/2, (ct.p1.y+ct.p2.y)/2)
+	}
+	if !c.fraying {
+		return
+	}
+	c.fray(ct.p1, ct.p2)
+	c.fray(ct.p2, ct.p1)
+}
+
+// fray attaches a thread to the `anchor` particle, pointing toward the `other` end of the torn stick.
+func (c *Cloth) fray(anchor, other *Particle) {
+	dx, dy := other.x-anchor.x, other.y-anchor.y
+	dist := distance(dx, dy)
+	if dist == 0 {
+		dx, dy, dist = 0, 1, 1
+	}
+	step := f32.Pt(float32(dx/dist*fraySegment), float32(dy/dist*fraySegment))
+	t := &thread{anchor: anchor}
+	for i := range t.pts {
+		t.pts[i] = f32.Pt(float32(anchor.x), float32(anchor.y)).Add(step.Mul(float32(i)))
+	}
+	t.prev = t.pts
+	c.frays = append(c.frays, t)
+	if len(c.frays) > maxFrays {
+		c.frays = c.frays[len(c.frays)-maxFrays:]
+	}
+}
+
+// updateFrays moves the dangling threads by the gravity and the wind, keeping them attached to their particles.
+// The threads which have been dangling long enough or lost their particle are removed.
+func (c *Cloth) updateFrays(delta float64) {
+	frays := c.frays[:0]
+	for _, t := range c.frays {
+		if t.age += delta; t.age > frayLife || !t.anchor.isActive {
+			continue
+		}
+		frays = append(frays, t)
+
+		wx, wy := c.windAt(t.anchor.x, t.anchor.y)
+		acc := f32.Pt(float32((c.forces.GravityX+c.forces.WindX+wx)*delta*delta), float32((c.forces.GravityY+c.forces.WindY+wy)*delta*delta))
+		for i := 1; i < len(t.pts); i++ {
+			pt := t.pts[i]
+			t.pts[i] = pt.Add(pt.Sub(t.prev[i]).Mul(1 - frayDrag)).Add(acc)
+			t.prev[i] = pt
+		}
+		t.pts[0] = f32.Pt(float32(t.anchor.x), float32(t.anchor.y))
+		t.prev[0] = t.pts[0]
+		// The segments are pulled back to their length from the anchor down to the loose end.
+		for i := 1; i < len(t.pts); i++ {
+			d := t.pts[i].Sub(t.pts[i-1])
+			if l := float32(distance(float64(d.X), float64(d.Y))); l > 0 {
+				t.pts[i] = t.pts[i-1].Add(d.Mul(fraySegment / l))
+			}
+		}
+	}
+	c.frays = frays
+}
+
+// drawFrays draws the dangling threads, fading out as they get older.
+func (c *Cloth) drawFrays(gtx layout.Context) {
+	for _, t := range c.frays {
+		var path clip.Path
+		path.Begin(gtx.Ops)
+		path.MoveTo(t.pts[0])
+		for _, pt := range t.pts[1:] {
+			path.LineTo(pt)
+		}
+		col := c.color
+		col.A = uint8(float64(col.A) * (1 - t.age/frayLife))
+		paint.FillShape(gtx.Ops, col, clip.Stroke{Path: path.End(), Width: 1}.Op())
+	}
+}
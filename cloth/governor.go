@@ -0,0 +1,83 @@
+package cloth
+
+import "time"
+
+// Governor adapts the number of physics sub-steps and constraint solver iterations
+// to a frame time budget. On fast frames it raises the sub-steps first (stability),
+// then the iterations (stiffness). On slow frames it lowers them in the reverse order,
+// and on a stalled frame it falls back to the minimum immediately, so the extra work
+// can never make the following frames even slower.
+type Governor struct {
+	budget     time.Duration
+	minSteps   int
+	maxSteps   int
+	minIter    int
+	maxIter    int
+	steps      int
+	iterations int
+}
+
+// NewGovernor creates a new governor. With a zero budget the sub-steps and
+// the iterations are fixed to their minimum values.
+func NewGovernor(budget time.Duration, minSteps, maxSteps, minIter, maxIter int) *Governor {
+	minSteps, minIter = maxInt(minSteps, 1), maxInt(minIter, 1)
+	return &Governor{
+		budget:     budget,
+		minSteps:   minSteps,
+		maxSteps:   maxInt(maxSteps, minSteps),
+		minIter:    minIter,
+		maxIter:    maxInt(maxIter, minIter),
+		steps:      minSteps,
+		iterations: minIter,
+	}
+}
+
+// Update adjusts the sub-steps and the iterations based on the time spent with the physics in the last frame.
+func (g *Governor) Update(elapsed time.Duration) {
+	switch {
+	case g.budget == 0:
+		return
+	case elapsed > 2*g.budget:
+		g.steps, g.iterations = g.minSteps, g.minIter
+	case elapsed > g.budget:
+		if g.iterations > g.minIter {
+			g.iterations--
+		} else if g.steps > g.minSteps {
+			g.steps--
+		}
+	case elapsed < g.budget*3/4:
+		if g.steps < g.maxSteps {
+			g.steps++
+		} else if g.iterations < g.maxIter {
+			g.iterations++
+		}
+	}
+}
+
+// SubSteps returns the number of sub-steps a physics step should be divided into.
+func (g *Governor) SubSteps() int {
+	return g.steps
+}
+
+// Iterations returns the number of constraint solver iterations per sub-step.
+func (g *Governor) Iterations() int {
+	return g.iterations
+}
+
+// SetIterations changes the number of constraint solver iterations,
+// extending the bounds of the adaptation if they don't include it.
+func (g *Governor) SetIterations(n int) {
+	n = maxInt(n, 1)
+	g.iterations = n
+	if n < g.minIter {
+		g.minIter = n
+	}
+	g.maxIter = maxInt(g.maxIter, n)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,131 @@
+package cloth
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// strainShades is the number of the colors the strain gradient is split into.
+// The sticks of the same color are drawn as a single clip path.
+const strainShades = 16
+
+// strainGradient is the default gradient of the strain shading, from the slack (blue) to the tearing (red) sticks.
+var strainGradient = Gradient{
+	{R: 0x30, G: 0x60, B: 0xe0, A: 0xff},
+	{R: 0xe0, G: 0x30, B: 0x20, A: 0xff},
+}
+
+// Gradient is a color gradient of evenly spaced color stops.
+type Gradient []color.NRGBA
+
+// At returns the color of the gradient at the position `t` between 0 and 1.
+func (g Gradient) At(t float64) color.NRGBA {
+	t = math.Max(0, math.Min(t, 1)) * float64(len(g)-1)
+	i := int(t)
+	if i >= len(g)-1 {
+		return g[len(g)-1]
+	}
+	f := t - float64(i)
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a) + (float64(b)-float64(a))*f))
+	}
+	a, b := g[i], g[i+1]
+	return color.NRGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: mix(a.A, b.A)}
+}
+
+// paint fills the rectangle with the gradient running from the left to the right if `horizontal` is set,
+// otherwise from the top to the bottom. The linear gradient op has only two stops, so every pair
+// of the stops fills a band of its own.
+func (g Gradient) paint(gtx layout.Context, rect image.Rectangle, horizontal bool) {
+	bands := len(g) - 1
+	for i := 0; i < bands; i++ {
+		band := rect
+		var stop1, stop2 f32.Point
+		if horizontal {
+			band.Min.X = rect.Min.X + rect.Dx()*i/bands
+			band.Max.X = rect.Min.X + rect.Dx()*(i+1)/bands
+			stop1, stop2 = f32.Pt(float32(band.Min.X), 0), f32.Pt(float32(band.Max.X), 0)
+		} else {
+			band.Min.Y = rect.Min.Y + rect.Dy()*i/bands
+			band.Max.Y = rect.Min.Y + rect.Dy()*(i+1)/bands
+			stop1, stop2 = f32.Pt(0, float32(band.Min.Y)), f32.Pt(0, float32(band.Max.Y))
+		}
+		area := clip.Rect(band).Push(gtx.Ops)
+		paint.LinearGradientOp{Stop1: stop1, Color1: g[i], Stop2: stop2, Color2: g[i+1]}.Add(gtx.Ops)
+		paint.PaintOp{}.Add(gtx.Ops)
+		area.Pop()
+	}
+}
+
+// ParseGradient parses a comma separated list of at least two hex colors, e.g. "#3060e0,#e03020".
+func ParseGradient(s string) (Gradient, error) {
+	var g Gradient
+	for _, item := range strings.Split(s, ",") {
+		col, err := parseColor(item)
+		if err != nil {
+			return nil, err
+		}
+		g = append(g, col)
+	}
+	if len(g) < 2 {
+		return nil, fmt.Errorf("the gradient needs at least two colors: %q", s)
+	}
+	return g, nil
+}
+
+// parseColor parses a hex color, e.g. "#3060e0".
+func parseColor(s string) (color.NRGBA, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil || len(s) != 6 {
+		return color.NRGBA{}, fmt.Errorf("invalid color: %q", s)
+	}
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// SetStrainGradient sets the gradient the strain render mode colors the sticks along.
+// The nil gradient is the default one.
+func (c *Cloth) SetStrainGradient(g Gradient) {
+	if g == nil {
+		g = strainGradient
+	}
+	c.gradient = g
+}
+
+// StrainGradient returns the gradient the strain render mode colors the sticks along.
+func (c *Cloth) StrainGradient() Gradient {
+	return c.gradient
+}
+
+// drawStrain draws the sticks colored by their strain, where the start of the gradient is the slack
+// stick and its end is the stick stretched to the tear distance, so it's visible where the cloth is about to rip.
+func (c *Cloth) drawStrain(gtx layout.Context, alpha float64) {
+	var shades [strainShades]clip.Path
+	var used [strainShades]bool
+	for _, ct := range c.constraints {
+		if !ct.p1.isActive || ct.kind != stickStructural {
+			continue
+		}
+		shade := int(math.Min(ct.strain, 1) * (strainShades - 1))
+		if !used[shade] {
+			shades[shade].Begin(gtx.Ops)
+			used[shade] = true
+		}
+		c.addStick(&shades[shade], ct, alpha)
+	}
+	for shade := range shades {
+		if used[shade] {
+			col := c.gradient.At(float64(shade) / (strainShades - 1))
+			c.fillSticks(gtx, col, &shades[shade])
+		}
+	}
+}
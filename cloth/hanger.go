@@ -0,0 +1,52 @@
+package cloth
+
+// hanger returns the indices of the pinned particles connected by the sticks to the pinned particle
+// closest to the {x, y} position within the radius `r`, e.g. the whole pinned row of the cloth.
+// It returns nil if there is no pinned particle around the position.
+func (c *Cloth) hanger(x, y, r float64) []int {
+	i := c.nearest(x, y, r)
+	if i < 0 || !c.particles[i].pinX {
+		return nil
+	}
+	index := make(map[*Particle]int, len(c.particles))
+	for i, p := range c.particles {
+		index[p] = i
+	}
+	// The connected pinned particles are collected by a flood fill along the sticks between them.
+	held := map[int]bool{i: true}
+	hanger := []int{i}
+	for n := 0; n < len(hanger); n++ {
+		p := c.particles[hanger[n]]
+		for _, ct := range c.constraints {
+			q := ct.p2
+			if ct.p2 == p {
+				q = ct.p1
+			} else if ct.p1 != p {
+				continue
+			}
+			if j := index[q]; q.pinX && q.isActive && !held[j] {
+				held[j] = true
+				hanger = append(hanger, j)
+			}
+		}
+	}
+	return hanger
+}
+
+// moveHanger moves the pinned particles held by the pointer as a rigid segment, following
+// the pointer motion since the last step, so the hanging cloth can be relocated or swung around.
+func (c *Cloth) moveHanger(mouse *Mouse) {
+	if len(mouse.hanger) == 0 || !mouse.sweeping {
+		return
+	}
+	dx, dy := mouse.x-mouse.sx, mouse.y-mouse.sy
+	for _, i := range mouse.hanger {
+		p := c.particles[i]
+		p.x += dx
+		p.y += dy
+		p.px, p.py = p.x, p.y
+	}
+	if dx != 0 || dy != 0 {
+		c.wake()
+	}
+}
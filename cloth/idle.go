@@ -0,0 +1,45 @@
+package cloth
+
+import "time"
+
+// idleMotion is the largest particle displacement in a step, under which the cloth is considered settled.
+// The soft sticks keep the crumpled cloth trembling by a fraction of a pixel, so it's never completely still.
+const idleMotion = 0.25
+
+// Idle detects when the cloth has settled and no input arrived for a given duration.
+// In the idle state the physics is not stepped and the window is not redrawn,
+// until an input event, a window resize or a force change wakes it up.
+type Idle struct {
+	after  time.Duration
+	since  time.Time
+	asleep bool
+}
+
+// NewIdle creates a new idle detector entering the idle state after `after` inactivity.
+// A zero duration disables the idle detection.
+func NewIdle(after time.Duration) *Idle {
+	return &Idle{after: after}
+}
+
+// Wake leaves the idle state and restarts the inactivity timer.
+func (i *Idle) Wake() {
+	i.asleep = false
+	i.since = time.Time{}
+}
+
+// Update reports whether the simulation is idle at `now`. The inactivity timer
+// only runs while the cloth is settled and it's restarted when it starts moving.
+func (i *Idle) Update(now time.Time, settled bool) bool {
+	if i.after == 0 || i.asleep {
+		return i.asleep
+	}
+	if !settled {
+		i.since = time.Time{}
+		return false
+	}
+	if i.since.IsZero() {
+		i.since = now
+	}
+	i.asleep = now.Sub(i.since) >= i.after
+	return i.asleep
+}
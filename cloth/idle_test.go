@@ -0,0 +1,40 @@
+package cloth
+
+import (
+	"image"
+	"testing"
+)
+
+// TestSettled lets the default cloth fall and checks that it settles, unless the wind keeps it moving.
+func TestSettled(t *testing.T) {
+	const (
+		width, height = 940, 580
+		steps         = 1800
+		delta         = 1.0 / 60
+	)
+	for _, windy := range []bool{false, true} {
+		w := NewClothWidget(nil, DefaultConfig())
+		w.world = image.Pt(width, height)
+		w.newCloth()
+		w.cloth.Init(w.startPosition())
+		if windy {
+			w.cloth.SetWindModel(WindModel{Enabled: true, Strength: 50})
+		}
+		settled := 0
+		for i := 0; i < steps; i++ {
+			w.cloth.keepPositions()
+			w.cloth.Step(w.mouse, width, height, delta)
+			if w.cloth.Settled() {
+				settled++
+			} else {
+				settled = 0
+			}
+		}
+		if windy && settled > 0 {
+			t.Errorf("the cloth in the wind has settled for %d steps", settled)
+		}
+		if !windy && settled == 0 {
+			t.Errorf("the cloth hasn't settled in %d steps", steps)
+		}
+	}
+}
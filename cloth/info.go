@@ -0,0 +1,61 @@
+package cloth
+
+// ParticleInfo is a read-only snapshot of a cloth particle. It's a copy of the particle
+// state at the time it has been requested, so it doesn't follow the live simulation.
+type ParticleInfo struct {
+	// Col and Row are the position of the particle in the cloth grid.
+	Col, Row int
+	// X and Y are the particle coordinates in pixels.
+	X, Y float64
+	// VX and VY are the particle velocity in pixels per simulation step.
+	VX, VY float64
+	Pinned bool
+}
+
+// StickInfo is a read-only snapshot of a cloth stick connecting two particles.
+type StickInfo struct {
+	A, B          ParticleInfo
+	RestLength    float64
+	CurrentLength float64
+}
+
+// info returns the snapshot of the particle.
+func (p *Particle) info() ParticleInfo {
+	return ParticleInfo{
+		Col:    p.col,
+		Row:    p.row,
+		X:      p.x,
+		Y:      p.y,
+		VX:     p.x - p.px,
+		VY:     p.y - p.py,
+		Pinned: p.pinX,
+	}
+}
+
+// Particles returns the snapshots of the cloth particles which haven't been torn off.
+func (c *Cloth) Particles() []ParticleInfo {
+	particles := make([]ParticleInfo, 0, len(c.particles))
+	for _, p := range c.particles {
+		if p.isActive {
+			particles = append(particles, p.info())
+		}
+	}
+	return particles
+}
+
+// Sticks returns the snapshots of the cloth sticks, the same ones which are drawn.
+func (c *Cloth) Sticks() []StickInfo {
+	sticks := make([]StickInfo, 0, len(c.constraints))
+	for _, ct := range c.constraints {
+		if !ct.p1.isActive || ct.kind != stickStructural {
+			continue
+		}
+		sticks = append(sticks, StickInfo{
+			A:             ct.p1.info(),
+			B:             ct.p2.info(),
+			RestLength:    ct.length,
+			CurrentLength: distance(ct.p1.x-ct.p2.x, ct.p1.y-ct.p2.y),
+		})
+	}
+	return sticks
+}
@@ -0,0 +1,325 @@
+package cloth
+
+import (
+	"image"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gioui.org/io/key"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+)
+
+// binding is a key binding of the cloth widget, which is also describing itself in the help overlay.
+type binding struct {
+	// keys are the bound keys in the format of key.Set, e.g. "Short-Z" or "(Shift)-I".
+	keys string
+	// label is the key combination shown in the help overlay.
+	label string
+	help  string
+	// hold is set for the bindings acting while the key is held down, which are also called on the key release.
+	hold   bool
+	action func(w *ClothWidget, e key.Event)
+}
+
+// gesture is a pointer gesture shown in the help overlay.
+type gesture struct {
+	label, help string
+}
+
+// bindings is the registry of all the key bindings of the cloth widget.
+var bindings = []binding{
+	{keys: key.NameSpace, label: "SPACE", help: "Reset the cloth to the default values",
+		action: func(w *ClothWidget, e key.Event) { w.Reset() }},
+	{keys: "Short-Z|Short-Shift-Z", label: "CTRL+Z/CTRL+SHIFT+Z", help: "Undo/redo the last tear or pin edit",
+		action: func(w *ClothWidget, e key.Event) {
+			if e.Modifiers.Contain(key.ModShift) {
+				w.Redo()
+			} else {
+				w.Undo()
+			}
+		}},
+	{keys: key.NameF5, label: "F5", help: "Take a snapshot of the cloth",
+		action: func(w *ClothWidget, e key.Event) {
+			w.timeline.Capture(w.cloth)
+			w.snapTime = time.Now()
+		}},
+	{keys: key.NameF2, label: "F2", help: "Show/hide the strain debug view",
+		action: func(w *ClothWidget, e key.Event) { w.strainView = !w.strainView }},
+	{keys: key.NameF3, label: "F3", help: "Show/hide the winding of the cells",
+		action: func(w *ClothWidget, e key.Event) { w.winding = !w.winding }},
+	{keys: key.NamePageUp + "|" + key.NamePageDown, label: "PAGE UP/PAGE DOWN", help: "Jump to the previous/next snapshot",
+		action: func(w *ClothWidget, e key.Event) {
+			if e.Name == key.NamePageUp {
+				w.timeline.Back(w.cloth)
+			} else {
+				w.timeline.Forward(w.cloth)
+			}
+		}},
+	{keys: "P", label: "P", help: "Pause/resume the simulation",
+		action: func(w *ClothWidget, e key.Event) { w.togglePause() }},
+	{keys: key.NameHome + "|" + key.NameEnd, label: "HOME/END", help: "Rewind/fast-forward the paused simulation",
+		action: func(w *ClothWidget, e key.Event) {
+			if !w.paused {
+				return
+			}
+			if e.Name == key.NameHome {
+				w.timeline.Seek(w.cloth, w.timeline.frame-scrubFrames)
+			} else {
+				w.timeline.Seek(w.cloth, w.timeline.frame+scrubFrames)
+			}
+		}},
+	{keys: ",|.", label: ",/.", help: "Step the paused simulation one frame backward/forward",
+		action: func(w *ClothWidget, e key.Event) {
+			if !w.paused {
+				return
+			}
+			if e.Name == "," {
+				w.timeline.Seek(w.cloth, w.timeline.frame-1)
+				return
+			}
+			// The diagnostics of a single step are always collected, so they can be inspected in the overlay.
+			w.cloth.SetDiagnostics(true)
+			// A frame of the timeline is a single sub-step of the running simulation, so it's advanced by the same delta.
+			w.cloth.keepPositions()
+			w.timeline.Advance(w.cloth, w.mouse, w.world.X, w.world.Y, w.stepper.Delta()/float64(w.governor.SubSteps()))
+			w.cloth.SetDiagnostics(w.config.DebugSolver)
+			w.stepped = true
+		}},
+	{keys: "[|]", label: "[/]", help: "Decrease/increase the gravity magnitude",
+		action: func(w *ClothWidget, e key.Event) {
+			if e.Name == "[" {
+				w.cloth.SetGravityMagnitude(w.cloth.GravityMagnitude() - gravityStep)
+			} else {
+				w.cloth.SetGravityMagnitude(w.cloth.GravityMagnitude() + gravityStep)
+			}
+		}},
+	{keys: key.NameUpArrow + "|" + key.NameDownArrow, label: "UP/DOWN", help: "Pull the gravity upward/downward",
+		action: func(w *ClothWidget, e key.Event) {
+			// Pulling the gravity upward past zero flips its direction.
+			step := float64(gravityStep)
+			if e.Name == key.NameUpArrow {
+				step = -step
+			}
+			gx, gy := w.cloth.Gravity()
+			w.cloth.SetGravity(gx, math.Max(-maxGravity, math.Min(gy+step, maxGravity)))
+		}},
+	{keys: key.NameLeftArrow + "|" + key.NameRightArrow, label: "LEFT/RIGHT", help: "Orbit the camera around the 3D cloth",
+		action: func(w *ClothWidget, e key.Event) {
+			if e.Name == key.NameLeftArrow {
+				w.cloth.Orbit(-orbitStep)
+			} else {
+				w.cloth.Orbit(orbitStep)
+			}
+		}},
+	{keys: "G", label: "G", help: "Flip the gravity direction",
+		action: func(w *ClothWidget, e key.Event) {
+			gx, gy := w.cloth.Gravity()
+			w.cloth.SetGravity(-gx, -gy)
+		}},
+	{keys: "W", label: "W", help: "Turn the wind on/off",
+		action: func(w *ClothWidget, e key.Event) { w.cloth.ToggleWind() }},
+	{keys: "C", label: "C", help: "Turn the self-collision on/off",
+		action: func(w *ClothWidget, e key.Event) { w.cloth.SetSelfCollision(!w.cloth.SelfCollision()) }},
+	{keys: "(Shift)-I", label: "I/SHIFT+I", help: "Increase/decrease the solver iterations",
+		action: func(w *ClothWidget, e key.Event) {
+			// The stiffness of the cloth grows with the number of solver iterations.
+			if e.Modifiers.Contain(key.ModShift) {
+				w.governor.SetIterations(w.governor.Iterations() - 1)
+			} else {
+				w.governor.SetIterations(w.governor.Iterations() + 1)
+			}
+		}},
+	{keys: "(Shift)-D", label: "D/SHIFT+D", help: "Increase/decrease the air drag",
+		action: func(w *ClothWidget, e key.Event) {
+			dx, dy := w.cloth.Drag()
+			if e.Modifiers.Contain(key.ModShift) {
+				w.cloth.SetDrag(dx-dragStep, dy-dragStep)
+			} else {
+				w.cloth.SetDrag(dx+dragStep, dy+dragStep)
+			}
+		}},
+	{keys: "(Shift)-T", label: "T/SHIFT+T", help: "Raise/lower the tear threshold",
+		action: func(w *ClothWidget, e key.Event) {
+			// Raising the threshold past the maximum makes the cloth untearable.
+			d := w.cloth.TearThreshold()
+			if e.Modifiers.Contain(key.ModShift) {
+				w.cloth.SetTearThreshold(math.Min(d, MaxTearDist) - tearDistStep)
+			} else {
+				w.cloth.SetTearThreshold(d + tearDistStep)
+			}
+		}},
+	{keys: "M", label: "M", help: "Switch between the elastic and the plastic material",
+		action: func(w *ClothWidget, e key.Event) { w.cloth.SetPlastic(!w.cloth.Plastic()) }},
+	{keys: "R", label: "R (hold)", help: "Repair the holes under the cursor while dragging", hold: true,
+		action: func(w *ClothWidget, e key.Event) { w.repairing = e.State == key.Press }},
+	{keys: "U", label: "U", help: "Put the cloth underwater",
+		action: func(w *ClothWidget, e key.Event) { w.cloth.SetUnderwater(!w.cloth.Underwater()) }},
+	{keys: "E", label: "E", help: "Turn the charged field around the cursor on/off",
+		action: func(w *ClothWidget, e key.Event) {
+			w.charged = !w.charged
+			if !w.charged {
+				w.mouse.setField(0)
+			}
+		}},
+	{keys: "X", label: "X", help: "Switch the pointer to scissors",
+		action: func(w *ClothWidget, e key.Event) { w.toggleTool(toolCut) }},
+	{keys: "V", label: "V", help: "Switch the pointer to a needle",
+		action: func(w *ClothWidget, e key.Event) { w.toggleTool(toolPull) }},
+	{keys: "A", label: "A (hold)", help: "Pull the cloth toward the cursor", hold: true,
+		action: func(w *ClothWidget, e key.Event) {
+			// Holding the attract key pulls the cloth toward the cursor, gathering it up until the key is released.
+			w.attracting = e.State == key.Press
+			if w.attracting {
+				w.mouse.setField(1)
+			} else if !w.charged {
+				w.mouse.setField(0)
+			}
+		}},
+	{keys: "F", label: "F", help: "Turn the blower on/off",
+		action: func(w *ClothWidget, e key.Event) { w.mouse.setBlowing(!w.mouse.blowing) }},
+	{keys: "L", label: "L", help: "Switch between drawing the sticks and filling the cloth",
+		action: func(w *ClothWidget, e key.Event) { w.cloth.SetFill(!w.cloth.Fill()) }},
+	{keys: "K", label: "K", help: "Switch the coloring of the sticks: plain, strain, rainbow or glow",
+		action: func(w *ClothWidget, e key.Event) {
+			w.cloth.SetRenderMode((w.cloth.RenderMode() + 1) % len(renderModeNames))
+		}},
+	{keys: "J", label: "J", help: "Show/hide the dots at the particles",
+		action: func(w *ClothWidget, e key.Event) { w.cloth.SetDots(!w.cloth.Dots()) }},
+	{keys: "Y", label: "Y", help: "Turn the motion trails on/off",
+		action: func(w *ClothWidget, e key.Event) { w.cloth.SetTrails(!w.cloth.Trails()) }},
+	{keys: "Q", label: "Q", help: "Switch the background: solid, gradient or image",
+		action: func(w *ClothWidget, e key.Event) { w.cycleBackground() }},
+	{keys: "O", label: "O", help: "Switch between the light and the dark color scheme",
+		action: func(w *ClothWidget, e key.Event) { w.SetDarkMode(!w.dark) }},
+	{keys: "H", label: "H", help: "Switch to the next color palette",
+		action: func(w *ClothWidget, e key.Event) { w.cyclePalette() }},
+	{keys: "S", label: "S", help: "Shake the cloth",
+		action: func(w *ClothWidget, e key.Event) {
+			// The consecutive shakes are jerking the cloth back and forth.
+			w.shakeDir = -w.shakeDir
+			if w.shakeDir == 0 {
+				w.shakeDir = 1
+			}
+			w.cloth.Shake(w.shakeDir*shakeImpulse, 0)
+			w.timeline.Capture(w.cloth)
+		}},
+	{keys: "B", label: "B", help: "Show/hide the ball",
+		action: func(w *ClothWidget, e key.Event) {
+			w.cloth.ToggleBall(float64(w.world.X)/2, float64(w.world.Y)*0.8)
+		}},
+	{keys: presetKeys(), label: "1-" + strconv.Itoa(len(PresetNames)), help: "Switch to the " + strings.Join(PresetNames, ", ") + " preset",
+		action: func(w *ClothWidget, e key.Event) {
+			// The number keys are switching between the presets.
+			if i := int(e.Name[0] - '1'); len(e.Name) == 1 && i >= 0 && i < len(PresetNames) {
+				w.SetPreset(presets[PresetNames[i]])
+			}
+		}},
+	{keys: "(Shift)-" + key.NameShift, label: "SHIFT (hold)", help: "Slow down the simulation", hold: true,
+		action: func(w *ClothWidget, e key.Event) {
+			if e.State == key.Press {
+				w.stepper.SetScale(slowMotion)
+			} else {
+				w.stepper.SetScale(1)
+			}
+		}},
+	{keys: key.NameDeleteBackward + "|" + key.NameDeleteForward, label: "DELETE", help: "Delete the selected particles",
+		action: func(w *ClothWidget, e key.Event) { w.editSelection(groupDelete) }},
+	{keys: "?|Shift-/|" + key.NameF1, label: "?/F1", help: "Show/hide this help",
+		action: func(w *ClothWidget, e key.Event) { w.help = !w.help }},
+}
+
+// gestures are the pointer gestures listed in the help overlay.
+var gestures = []gesture{
+	{"DRAG", "Push the cloth or use the selected tool"},
+	{"DRAG A PIN", "Move the pinned particles connected to it"},
+	{"RIGHT CLICK", "Open the context menu"},
+	{"SCROLL", "Resize the focus area around the cursor"},
+	{"CTRL+SCROLL", "Zoom the view"},
+	{"MIDDLE DRAG", "Pan the view"},
+	{"CTRL+CLICK", "Pin up or release a particle"},
+	{"SHIFT+CLICK", "Place an obstacle"},
+	{"DOUBLE CLICK", "Blow up the cloth"},
+	{"LEFT CLICK+HOLD", "Increase the pressure"},
+}
+
+// bindingKeys returns the set of all the bound keys.
+func bindingKeys() key.Set {
+	keys := make([]string, len(bindings))
+	for i, b := range bindings {
+		keys[i] = b.keys
+	}
+	return key.Set(strings.Join(keys, "|"))
+}
+
+// matches reports whether the key event is one of the keys of the binding, with the same modifiers held down.
+func (b *binding) matches(e key.Event) bool {
+	return key.Set(b.keys).Contains(e.Name, e.Modifiers)
+}
+
+// handleKey calls the first key binding matching the key event.
+func (w *ClothWidget) handleKey(e key.Event) {
+	for i := range bindings {
+		b := &bindings[i]
+		if (b.hold || e.State == key.Press) && b.matches(e) {
+			b.action(w, e)
+			return
+		}
+	}
+}
+
+// releaseHolds releases every held key binding, since the release of a held key isn't delivered
+// once the widget has lost the focus. The repair started by a held key is ended too.
+func (w *ClothWidget) releaseHolds() {
+	for i := range bindings {
+		if b := &bindings[i]; b.hold {
+			b.action(w, key.Event{State: key.Release})
+		}
+	}
+	if w.mouse.stitching {
+		w.mouse.setStitching(false)
+		w.cloth.history.Commit()
+	}
+}
+
+// drawHelp draws the overlay listing the key bindings and the pointer gestures.
+func (w *ClothWidget) drawHelp(gtx layout.Context) {
+	if !w.help || w.Theme == nil {
+		return
+	}
+	var labels, helps []string
+	for _, b := range bindings {
+		labels, helps = append(labels, b.label), append(helps, b.help)
+	}
+	for _, g := range gestures {
+		labels, helps = append(labels, g.label), append(helps, g.help)
+	}
+
+	macro := op.Record(gtx.Ops)
+	gtx.Constraints.Min = image.Point{}
+	dims := layout.Stack{}.Layout(gtx,
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			paint.FillShape(gtx.Ops, w.scheme.Panel, clip.Rect{Max: gtx.Constraints.Min}.Op())
+			return layout.Dimensions{Size: gtx.Constraints.Min}
+		}),
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			return layout.UniformInset(unit.Dp(12)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{}.Layout(gtx,
+					layout.Rigid(material.Body2(w.Theme, strings.Join(labels, "\n")).Layout),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(16)}.Layout),
+					layout.Rigid(material.Body2(w.Theme, strings.Join(helps, "\n")).Layout),
+				)
+			})
+		}),
+	)
+	call := macro.Stop()
+
+	defer op.Offset(w.size.Sub(dims.Size).Div(2)).Push(gtx.Ops).Pop()
+	call.Add(gtx.Ops)
+}
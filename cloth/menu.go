@@ -0,0 +1,156 @@
+package cloth
+
+import (
+	"image"
+
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// The tools of the primary pointer, which can be selected from the context menu.
+const (
+	// toolPush drags the cloth around.
+	toolPush = iota
+	// toolPull pulls a single particle with the needle.
+	toolPull
+	// toolCut cuts the sticks with the scissors.
+	toolCut
+	// toolPin pins up or releases the particle under the pointer.
+	toolPin
+	// toolTear makes holes in the cloth under the focus area.
+	toolTear
+	// toolThrow throws projectiles in the direction of the drag.
+	toolThrow
+	// toolSelect selects the particles inside a lasso for the group operations.
+	toolSelect
+)
+
+// menuWidth is the width of the context menu.
+const menuWidth = 160
+
+// menuItem is an entry of the context menu calling its action when clicked.
+type menuItem struct {
+	label  string
+	action func(w *ClothWidget)
+	shown  func(w *ClothWidget) bool // the optional condition of showing the entry
+	click  widget.Clickable
+}
+
+// Menu is the context menu opened by the right click, which offers switching the tools and the presets
+// and resetting the cloth, so these features are discoverable without knowing the key bindings.
+type Menu struct {
+	open  bool
+	pos   image.Point
+	items []*menuItem
+}
+
+// newMenu creates the context menu with an entry for every tool and preset.
+func newMenu() *Menu {
+	m := &Menu{}
+	tools := []string{
+		toolPush:   "Push",
+		toolPull:   "Pull",
+		toolCut:    "Cut",
+		toolPin:    "Pin",
+		toolTear:   "Tear",
+		toolThrow:  "Throw",
+		toolSelect: "Select",
+	}
+	for tool, label := range tools {
+		tool := tool
+		m.add(label, func(w *ClothWidget) { w.setTool(tool) })
+	}
+	for _, name := range PresetNames {
+		p := presets[name]
+		m.add("Preset: "+name, func(w *ClothWidget) { w.SetPreset(p) })
+	}
+	m.add("Reset", (*ClothWidget).Reset)
+	groups := []string{
+		groupDelete: "Delete selection",
+		groupPin:    "Pin selection",
+		groupUnpin:  "Unpin selection",
+		groupMass:   "Add mass to selection",
+		groupPush:   "Push selection",
+	}
+	for op, label := range groups {
+		op := op
+		item := m.add(label, func(w *ClothWidget) { w.editSelection(op) })
+		// The group operations are only offered while there is a selection.
+		item.shown = func(w *ClothWidget) bool { return len(w.selection) > 0 }
+	}
+	return m
+}
+
+// add adds a new entry to the menu.
+func (m *Menu) add(label string, action func(w *ClothWidget)) *menuItem {
+	item := &menuItem{label: label, action: action}
+	m.items = append(m.items, item)
+	return item
+}
+
+// show opens the menu at the {x, y} position.
+func (m *Menu) show(x, y int) {
+	m.open, m.pos = true, image.Pt(x, y)
+}
+
+// Layout calls the actions of the clicked entries and draws the open menu, kept inside the widget bounds.
+func (m *Menu) Layout(gtx layout.Context, w *ClothWidget) {
+	for _, item := range m.items {
+		if item.click.Clicked() {
+			m.open = false
+			item.action(w)
+		}
+	}
+	if !m.open {
+		return
+	}
+
+	macro := op.Record(gtx.Ops)
+	gtx.Constraints.Min = image.Point{}
+	dims := layout.Stack{}.Layout(gtx,
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			paint.FillShape(gtx.Ops, w.scheme.Panel, clip.Rect{Max: gtx.Constraints.Min}.Op())
+			return layout.Dimensions{Size: gtx.Constraints.Min}
+		}),
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			var children []layout.FlexChild
+			for _, item := range m.items {
+				if item.shown != nil && !item.shown(w) {
+					continue
+				}
+				item := item
+				children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Dp(menuWidth)
+					gtx.Constraints.Max.X = gtx.Constraints.Min.X
+					return material.Clickable(gtx, &item.click, func(gtx layout.Context) layout.Dimensions {
+						return layout.UniformInset(unit.Dp(6)).Layout(gtx,
+							material.Body2(w.Theme, item.label).Layout)
+					})
+				}))
+			}
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+		}),
+	)
+	call := macro.Stop()
+
+	pos, max := m.pos, w.size.Sub(dims.Size)
+	if pos.X > max.X {
+		pos.X = max.X
+	}
+	if pos.Y > max.Y {
+		pos.Y = max.Y
+	}
+	if pos.X < 0 {
+		pos.X = 0
+	}
+	if pos.Y < 0 {
+		pos.Y = 0
+	}
+	defer op.Offset(pos).Push(gtx.Ops).Pop()
+	call.Add(gtx.Ops)
+}
@@ -0,0 +1,268 @@
+package cloth
+
+import (
+	"gioui.org/f32"
+	"gioui.org/io/pointer"
+	"gioui.org/unit"
+)
+
+// Touch is the position of a pointer, e.g. a finger on a touch screen, which is dragging the cloth.
+type Touch struct {
+	id     pointer.ID
+	x, y   float64
+	px, py float64
+	// sx and sy are the start of the path swept by the pointer since the last physics step.
+	sx, sy   float64
+	sweeping bool
+}
+
+// move moves the pointer to the {x, y} position.
+func (t *Touch) move(x, y float64) {
+	t.px, t.py = t.x, t.y
+	t.x, t.y = x, y
+}
+
+// sweepDistance returns the distance of the {x, y} point from the path the pointer moved along since
+// the last physics step. The path is approximated by a straight segment, so the fast cursor movements
+// are affecting every particle they crossed and not only the ones around the last position.
+func (t *Touch) sweepDistance(x, y float64) float64 {
+	if !t.sweeping {
+		return distance(x-t.x, y-t.y)
+	}
+	return segmentDistance(x, y, t.sx, t.sy, t.x, t.y)
+}
+
+// endSweep starts a new swept path at the current pointer position.
+func (t *Touch) endSweep() {
+	t.sx, t.sy = t.x, t.y
+	t.sweeping = true
+}
+
+// Mouse tracks the state of the primary pointer, which is driving all the tools,
+// and the additional touches, which are only dragging and tearing the cloth.
+type Mouse struct {
+	Touch
+	touches    []Touch
+	force      float64
+	scrollY    unit.Dp
+	maxScrollY unit.Dp
+	metric     unit.Metric
+	camera     *Camera // maps the pointer positions to the world, nil if they are already in the world
+	// unproject maps the world position on the perspective view of the 3D cloth onto the flat cloth, if it's set.
+	unproject  func(f32.Point) f32.Point
+	leftDown   bool
+	rightDown  bool
+	isDragging bool
+	field      int  // the charge of the field around the cursor: 1 attracts, -1 repels, 0 is off
+	cutting    bool // the scissors are cutting along the swept path
+	stitching  bool // the repair tool is stitching the holes in the focus area
+	threaded   bool // the needle tool is holding the particle at the needle index
+	needle     int
+	hanger     []int // the pinned particles moved by the pointer
+	blowing    bool  // the blower is blowing air in the {dirX, dirY} direction the cursor is moving
+	dirX       float64
+	dirY       float64
+}
+
+func (m *Mouse) updatePosition(x, y float64) {
+	m.move(x, y)
+	if dx, dy := x-m.px, y-m.py; dx != 0 || dy != 0 {
+		d := distance(dx, dy)
+		m.dirX, m.dirY = dx/d, dy/d
+	}
+}
+
+// getCurrentPosition returns the pointer position in the coordinate space of the cloth.
+// Gio reports the pointer positions in pixels on every platform (desktop, wasm and touch devices),
+// while the cloth is laid out in Dp, so they are divided by the screen density of the metric and
+// mapped through the zoom and the pan of the camera and the perspective of the 3D cloth.
+// Every pointer position should be resolved here.
+func (m *Mouse) getCurrentPosition(ev pointer.Event) f32.Point {
+	pos := ev.Position
+	if m.camera != nil {
+		pos = m.camera.toWorld(pos)
+	}
+	if m.unproject != nil {
+		pos = m.unproject(pos)
+	}
+	return pos
+}
+
+// getButtons returns the buttons pressed during a pointer event. Touch events don't report
+// any button, so pressing and dragging with the finger is treated like the primary button.
+func (m *Mouse) getButtons(ev pointer.Event) pointer.Buttons {
+	if ev.Source == pointer.Touch && (ev.Type == pointer.Press || ev.Type == pointer.Drag) {
+		return pointer.ButtonPrimary
+	}
+	return ev.Buttons
+}
+
+// getScrollDelta returns the vertical scroll amount in Dp. The scroll distance is reported in pixels,
+// so it has to be converted by the screen density to get the same focus area change on every device.
+func (m *Mouse) getScrollDelta(ev pointer.Event) unit.Dp {
+	if m.metric.PxPerDp == 0 {
+		return unit.Dp(ev.Scroll.Y)
+	}
+	return unit.Dp(ev.Scroll.Y / m.metric.PxPerDp)
+}
+
+// endSweep starts new swept paths at the current positions of the primary pointer and the touches.
+func (m *Mouse) endSweep() {
+	m.Touch.endSweep()
+	for i := range m.touches {
+		m.touches[i].endSweep()
+	}
+}
+
+// touch returns the additional touch with the pointer `id`, or nil if it's not tracked.
+func (m *Mouse) touch(id pointer.ID) *Touch {
+	for i := range m.touches {
+		if m.touches[i].id == id {
+			return &m.touches[i]
+		}
+	}
+	return nil
+}
+
+// addTouch starts tracking an additional touch at the {x, y} position.
+func (m *Mouse) addTouch(id pointer.ID, x, y float64) {
+	t := Touch{id: id, x: x, y: y, px: x, py: y}
+	t.endSweep()
+	// The touches are never modified in place, because the recorded inputs are sharing them.
+	m.touches = append(m.touches[:len(m.touches):len(m.touches)], t)
+}
+
+// moveTouch moves the additional touch with the pointer `id` to the {x, y} position.
+func (m *Mouse) moveTouch(id pointer.ID, x, y float64) {
+	touches := append([]Touch(nil), m.touches...)
+	for i := range touches {
+		if touches[i].id == id {
+			touches[i].move(x, y)
+		}
+	}
+	m.touches = touches
+}
+
+// removeTouch stops tracking the additional touch with the pointer `id`.
+func (m *Mouse) removeTouch(id pointer.ID) {
+	var touches []Touch
+	for _, t := range m.touches {
+		if t.id != id {
+			touches = append(touches, t)
+		}
+	}
+	m.touches = touches
+}
+
+// tearing reports whether any pointer is dragging the cloth, so the overstretched sticks are torn.
+func (m *Mouse) tearing() bool {
+	return m.isDragging || len(m.touches) > 0
+}
+
+func (m *Mouse) setCutting(cutting bool) {
+	m.cutting = cutting
+}
+
+func (m *Mouse) setStitching(stitching bool) {
+	m.stitching = stitching
+}
+
+// thread attaches the particle at the index `i` to the needle tool.
+func (m *Mouse) thread(i int) {
+	m.needle, m.threaded = i, true
+}
+
+// grab attaches the pinned particles at the `hanger` indices to the pointer.
+func (m *Mouse) grab(hanger []int) {
+	m.hanger = hanger
+}
+
+// drop releases the pinned particles held by the pointer.
+func (m *Mouse) drop() {
+	m.hanger = nil
+}
+
+// unthread releases the particle held by the needle tool.
+func (m *Mouse) unthread() {
+	m.threaded = false
+}
+
+func (m *Mouse) setBlowing(blowing bool) {
+	m.blowing = blowing
+}
+
+func (m *Mouse) setMetric(metric unit.Metric) {
+	m.metric = metric
+}
+
+func (m *Mouse) setLeftButton() {
+	m.leftDown = true
+}
+
+func (m *Mouse) releaseLeftButton() {
+	m.leftDown = false
+}
+
+func (m *Mouse) getLeftButton() bool {
+	return m.leftDown
+}
+
+func (m *Mouse) setRightButton() {
+	m.rightDown = true
+}
+
+func (m *Mouse) releaseRightButton() {
+	m.rightDown = false
+}
+
+func (m *Mouse) getRightButton() bool {
+	return m.rightDown
+}
+
+func (m *Mouse) setDragging(dragging bool) {
+	m.isDragging = dragging
+}
+
+func (m *Mouse) getDragging() bool {
+	return m.isDragging
+}
+
+// pulling reports whether the cloth is pulled by the pointer, the needle, the hanger or a touch.
+func (m *Mouse) pulling() bool {
+	return m.isDragging || m.threaded || len(m.hanger) > 0 || len(m.touches) > 0
+}
+
+func (m *Mouse) setField(charge int) {
+	m.field = charge
+}
+
+func (m *Mouse) increaseForce(force float64) {
+	m.force = force
+}
+
+func (m *Mouse) getForce() float64 {
+	return m.force
+}
+
+func (m *Mouse) resetForce() {
+	m.force = 0
+}
+
+func (m *Mouse) setScrollY(scrollY unit.Dp) {
+	m.scrollY = scrollY
+}
+
+func (m *Mouse) getScrollY() unit.Dp {
+	return m.scrollY
+}
+
+// getFocusArea returns the radius of the mouse focus area, which is modified by scrolling.
+func (m *Mouse) getFocusArea() float64 {
+	focusArea := m.getScrollY() + defFocusArea
+	if focusArea > maxFocusArea {
+		focusArea = maxFocusArea
+	} else if focusArea < minFocusArea {
+		focusArea = minFocusArea
+	}
+	return float64(focusArea)
+}
@@ -0,0 +1,83 @@
+package cloth
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"gioui.org/f32"
+	"gioui.org/io/pointer"
+	"gioui.org/io/router"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/unit"
+)
+
+// TestPointerPosition feeds synthetic pointer events to the widget on screens of different densities
+// and zoomed views, and checks that the pointer is tracked in the world coordinates of the cloth.
+func TestPointerPosition(t *testing.T) {
+	tests := []struct {
+		name    string
+		density float32
+		zoom    float32
+		offset  f32.Point
+	}{
+		{"unscaled", 1, 1, f32.Point{}},
+		{"hidpi", 2, 1, f32.Point{}},
+		{"ldpi", 0.75, 1, f32.Point{}},
+		{"zoomed", 2, 1.5, f32.Pt(-40, 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewClothWidget(nil, DefaultConfig())
+			var r router.Router
+			ops := new(op.Ops)
+			frame := func() {
+				ops.Reset()
+				w.Layout(layout.Context{
+					Ops:         ops,
+					Metric:      unit.Metric{PxPerDp: tt.density, PxPerSp: tt.density},
+					Constraints: layout.Exact(image.Pt(800, 600)),
+					Now:         time.Now(),
+					Queue:       &r,
+				})
+				r.Frame(ops)
+			}
+			frame()
+			w.camera.zoom, w.camera.offset = tt.zoom, tt.offset
+
+			world := func(p f32.Point) f32.Point {
+				return p.Sub(tt.offset).Div(tt.zoom * tt.density)
+			}
+			events := []pointer.Event{
+				{Type: pointer.Move, Source: pointer.Mouse, Position: f32.Pt(300, 200)},
+				{Type: pointer.Press, Source: pointer.Mouse, Buttons: pointer.ButtonPrimary, Position: f32.Pt(320, 240)},
+				// The router turns the moves of a pressed pointer into drags.
+				{Type: pointer.Move, Source: pointer.Mouse, Buttons: pointer.ButtonPrimary, Position: f32.Pt(360, 300)},
+			}
+			for _, ev := range events {
+				r.Queue(ev)
+				frame()
+				if got, want := f32.Pt(float32(w.mouse.x), float32(w.mouse.y)), world(ev.Position); !near(got, want) {
+					t.Errorf("%v at %v: pointer at %v, want %v", ev.Type, ev.Position, got, want)
+				}
+			}
+
+			// A second finger is tracked as an additional touch.
+			touch := pointer.Event{Type: pointer.Press, Source: pointer.Touch, PointerID: 1, Position: f32.Pt(500, 100)}
+			r.Queue(touch)
+			frame()
+			if tc := w.mouse.touch(touch.PointerID); tc == nil {
+				t.Errorf("touch at %v not tracked", touch.Position)
+			} else if got, want := f32.Pt(float32(tc.x), float32(tc.y)), world(touch.Position); !near(got, want) {
+				t.Errorf("touch at %v: at %v, want %v", touch.Position, got, want)
+			}
+		})
+	}
+}
+
+// near reports whether the two points are the same up to the float32 rounding of the transformation.
+func near(a, b f32.Point) bool {
+	d := a.Sub(b)
+	return d.X*d.X+d.Y*d.Y < 1e-6
+}
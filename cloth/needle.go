@@ -0,0 +1,21 @@
+package cloth
+
+const (
+	// needleDist is the largest distance of the particle picked up by the needle tool from the cursor.
+	needleDist = 20
+	// needleStiffness is the fraction of the distance to the cursor the needle pulls its particle by in every iteration.
+	needleStiffness = 0.9
+)
+
+// pullNeedle pulls the particle held by the needle tool to the cursor position.
+func (c *Cloth) pullNeedle(mouse *Mouse) {
+	if !mouse.threaded || mouse.needle >= len(c.particles) {
+		return
+	}
+	p := c.particles[mouse.needle]
+	if !p.isActive || p.pinX {
+		return
+	}
+	p.x += fround((mouse.x - p.x) * needleStiffness)
+	p.y += fround((mouse.y - p.y) * needleStiffness)
+}
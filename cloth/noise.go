@@ -0,0 +1,77 @@
+package cloth
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Noise is a seeded three dimensional Perlin noise generator,
+// based on Ken Perlin's improved noise reference implementation.
+type Noise struct {
+	perm [512]int
+}
+
+// NewNoise creates a new noise generator which permutation table is shuffled using the `seed`.
+func NewNoise(seed int64) *Noise {
+	n := &Noise{}
+	p := rand.New(rand.NewSource(seed)).Perm(256)
+	for i := range n.perm {
+		n.perm[i] = p[i&255]
+	}
+	return n
+}
+
+// At returns the noise value at the {x, y, z} coordinates, in the [-1, 1] range.
+func (n *Noise) At(x, y, z float64) float64 {
+	fx, fy, fz := math.Floor(x), math.Floor(y), math.Floor(z)
+	X, Y, Z := int(fx)&255, int(fy)&255, int(fz)&255
+	x, y, z = x-fx, y-fy, z-fz
+	u, v, w := fade(x), fade(y), fade(z)
+
+	p := &n.perm
+	a := p[X] + Y
+	aa, ab := p[a]+Z, p[a+1]+Z
+	b := p[X+1] + Y
+	ba, bb := p[b]+Z, p[b+1]+Z
+
+	return lerp(w,
+		lerp(v,
+			lerp(u, grad(p[aa], x, y, z), grad(p[ba], x-1, y, z)),
+			lerp(u, grad(p[ab], x, y-1, z), grad(p[bb], x-1, y-1, z))),
+		lerp(v,
+			lerp(u, grad(p[aa+1], x, y, z-1), grad(p[ba+1], x-1, y, z-1)),
+			lerp(u, grad(p[ab+1], x, y-1, z-1), grad(p[bb+1], x-1, y-1, z-1))))
+}
+
+// fade is the 6t^5-15t^4+10t^3 smoothing curve of the interpolation.
+func fade(t float64) float64 {
+	return t * t * t * (fround(t*(fround(t*6)-15)) + 10)
+}
+
+// lerp interpolates linearly between `a` and `b`.
+func lerp(t, a, b float64) float64 {
+	return a + fround(t*(b-a))
+}
+
+// grad returns the dot product of the {x, y, z} vector with one of the 12 gradient
+// directions, picked by the low 4 bits of the hash.
+func grad(hash int, x, y, z float64) float64 {
+	h := hash & 15
+	u := y
+	if h < 8 {
+		u = x
+	}
+	v := z
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
@@ -0,0 +1,239 @@
+package cloth
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+const (
+	// obstacleRadius is the radius of the circles placed with SHIFT+CLICK.
+	obstacleRadius = 40
+	// segmentWidth is the thickness of the line segment obstacles.
+	segmentWidth = 6
+	// ballRadius is the radius of the draggable ball.
+	ballRadius = 50
+)
+
+var (
+	// obstacleColor is the fill color of the obstacles.
+	obstacleColor = color.NRGBA{R: 0x6c, G: 0x7a, B: 0x89, A: 0xff}
+	// ballColor is the fill color of the draggable ball.
+	ballColor = color.NRGBA{R: 0xd9, G: 0x5d, B: 0x39, A: 0xff}
+)
+
+// Obstacle is a static collider the cloth drapes over. The particles penetrating
+// the obstacle are pushed back to its surface by the constraint solver.
+type Obstacle interface {
+	// resolve returns the closest position to {x, y} which is outside of the obstacle.
+	resolve(x, y float64) (float64, float64)
+	// draw draws the obstacle.
+	draw(gtx layout.Context)
+}
+
+// Circle is a circle obstacle centered at {X, Y} with the radius R.
+type Circle struct {
+	X, Y, R float64
+}
+
+// Box is an axis-aligned rectangle obstacle between the {X0, Y0} and {X1, Y1} corners.
+type Box struct {
+	X0, Y0, X1, Y1 float64
+}
+
+// Segment is a line segment obstacle between the {X0, Y0} and {X1, Y1} points.
+type Segment struct {
+	X0, Y0, X1, Y1 float64
+}
+
+func (c Circle) resolve(x, y float64) (float64, float64) {
+	dx, dy := x-c.X, y-c.Y
+	dist := distance(dx, dy)
+	if dist >= c.R {
+		return x, y
+	}
+	if dist == 0 {
+		return x, c.Y - c.R
+	}
+	return c.X + fround(dx/dist*c.R), c.Y + fround(dy/dist*c.R)
+}
+
+func (c Circle) draw(gtx layout.Context) {
+	c.fill(gtx, obstacleColor)
+}
+
+// fill fills the circle with the color.
+func (c Circle) fill(gtx layout.Context, col color.NRGBA) {
+	rect := image.Rect(int(c.X-c.R), int(c.Y-c.R), int(c.X+c.R), int(c.Y+c.R))
+	paint.FillShape(gtx.Ops, col, clip.Ellipse(rect).Op(gtx.Ops))
+}
+
+// contains reports whether the {x, y} point is inside the circle.
+func (c Circle) contains(x, y float64) bool {
+	return distance(x-c.X, y-c.Y) < c.R
+}
+
+func (b Box) resolve(x, y float64) (float64, float64) {
+	if x <= b.X0 || x >= b.X1 || y <= b.Y0 || y >= b.Y1 {
+		return x, y
+	}
+	// Push the point out through the nearest side of the box.
+	left, right, top, bottom := x-b.X0, b.X1-x, y-b.Y0, b.Y1-y
+	switch math.Min(math.Min(left, right), math.Min(top, bottom)) {
+	case top:
+		return x, b.Y0
+	case left:
+		return b.X0, y
+	case right:
+		return b.X1, y
+	default:
+		return x, b.Y1
+	}
+}
+
+func (b Box) draw(gtx layout.Context) {
+	rect := image.Rect(int(b.X0), int(b.Y0), int(b.X1), int(b.Y1))
+	paint.FillShape(gtx.Ops, obstacleColor, clip.Rect(rect).Op())
+}
+
+func (s Segment) resolve(x, y float64) (float64, float64) {
+	// Find the closest point of the segment.
+	sx, sy := s.X1-s.X0, s.Y1-s.Y0
+	t := 0.0
+	if l := fround(sx*sx) + fround(sy*sy); l > 0 {
+		t = (fround((x-s.X0)*sx) + fround((y-s.Y0)*sy)) / l
+	}
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	cx, cy := s.X0+fround(t*sx), s.Y0+fround(t*sy)
+	return Circle{X: cx, Y: cy, R: segmentWidth / 2}.resolve(x, y)
+}
+
+func (s Segment) draw(gtx layout.Context) {
+	var path clip.Path
+	path.Begin(gtx.Ops)
+	path.MoveTo(f32.Pt(float32(s.X0), float32(s.Y0)))
+	path.LineTo(f32.Pt(float32(s.X1), float32(s.Y1)))
+	paint.FillShape(gtx.Ops, obstacleColor, clip.Stroke{Path: path.End(), Width: segmentWidth}.Op())
+}
+
+// collideObstacles pushes the particles out of the obstacles.
+func (c *Cloth) collideObstacles() {
+	// The friction is applied in every solver iteration, so it's divided between them.
+	friction := c.friction / float64(c.iterations)
+	for _, p := range c.particles {
+		if !p.isActive || p.pinX {
+			continue
+		}
+		x, y := p.x, p.y
+		for _, o := range c.obstacles {
+			p.x, p.y = o.resolve(p.x, p.y)
+		}
+		if c.ballOn {
+			p.x, p.y = c.ball.resolve(p.x, p.y)
+		}
+		if friction > 0 && (p.x != x || p.y != y) {
+			p.rub(p.x-x, p.y-y, friction)
+		}
+	}
+}
+
+// rub slows down the particle sliding along a collider surface, where {nx, ny} is the direction
+// of the surface normal. The `friction` fraction of the tangential velocity is lost.
+func (p *Particle) rub(nx, ny, friction float64) {
+	l := distance(nx, ny)
+	nx, ny = nx/l, ny/l
+	vx, vy := p.x-p.px, p.y-p.py
+	vn := fround(vx*nx) + fround(vy*ny)
+	tx, ty := vx-fround(vn*nx), vy-fround(vn*ny)
+	p.px += fround(tx * friction)
+	p.py += fround(ty * friction)
+}
+
+// holdRails keeps the sliding pins at their vertical position.
+func (c *Cloth) holdRails() {
+	for _, p := range c.particles {
+		if p.rail && !p.pinX {
+			p.y, p.py = p.railY, p.railY
+		}
+	}
+}
+
+// ToggleBall shows or hides the draggable ball, which pushes the cloth out of its way.
+// The ball is placed at the {x, y} position when it's shown.
+func (c *Cloth) ToggleBall(x, y float64) {
+	c.ballOn = !c.ballOn
+	c.ball = Circle{X: x, Y: y, R: ballRadius}
+}
+
+// MoveBall moves the draggable ball to the {x, y} position.
+func (c *Cloth) MoveBall(x, y float64) {
+	c.ball.X, c.ball.Y = x, y
+}
+
+// Ball returns the draggable ball and whether it's shown.
+func (c *Cloth) Ball() (Circle, bool) {
+	return c.ball, c.ballOn
+}
+
+// AddObstacle adds a static obstacle to the scene.
+func (c *Cloth) AddObstacle(o Obstacle) {
+	// Never modify the obstacles in place, because the recorded settings are sharing them.
+	c.obstacles = append(c.obstacles[:len(c.obstacles):len(c.obstacles)], o)
+}
+
+// ClearObstacles removes all the obstacles from the scene.
+func (c *Cloth) ClearObstacles() {
+	c.obstacles = nil
+}
+
+// ParseObstacles parses a semicolon separated list of obstacles, where each
+// obstacle is given by its kind and its comma separated coordinates:
+//
+//	circle:x,y,r;box:x0,y0,x1,y1;line:x0,y0,x1,y1
+func ParseObstacles(s string) ([]Obstacle, error) {
+	var obstacles []Obstacle
+	for _, item := range strings.Split(s, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kind, args, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("missing obstacle coordinates: %q", item)
+		}
+		var v []float64
+		for _, arg := range strings.Split(args, ",") {
+			f, err := strconv.ParseFloat(strings.TrimSpace(arg), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid obstacle coordinate in %q: %w", item, err)
+			}
+			v = append(v, f)
+		}
+		switch {
+		case kind == "circle" && len(v) == 3:
+			obstacles = append(obstacles, Circle{X: v[0], Y: v[1], R: v[2]})
+		case kind == "box" && len(v) == 4:
+			obstacles = append(obstacles, Box{
+				X0: math.Min(v[0], v[2]), Y0: math.Min(v[1], v[3]),
+				X1: math.Max(v[0], v[2]), Y1: math.Max(v[1], v[3]),
+			})
+		case kind == "line" && len(v) == 4:
+			obstacles = append(obstacles, Segment{X0: v[0], Y0: v[1], X1: v[2], Y1: v[3]})
+		default:
+			return nil, fmt.Errorf("invalid obstacle: %q", item)
+		}
+	}
+	return obstacles, nil
+}
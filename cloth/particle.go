@@ -0,0 +1,294 @@
+package cloth
+
+import (
+	"image/color"
+	"math"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+const (
+	clothTearDist  = 60
+	clothPinDist   = 12
+	gravityForce   = 600
+	gravityStep    = 100
+	maxGravity     = 3000
+	defFocusArea   = 50
+	minFocusArea   = 30
+	maxFocusArea   = 150
+	mouseDragForce = 4.2
+	maxDragForce   = 20
+	stickTearDist  = 150
+	tearDistStep   = 25
+	minMass        = 0.01
+	dragStep       = 0.005
+	maxDrag        = 0.5
+	defUndoDepth   = 100
+)
+
+// MinTearDist and MaxTearDist are limiting the tear threshold. The thresholds over MaxTearDist are disabling the tearing.
+const (
+	MinTearDist = 10
+	MaxTearDist = 500
+)
+
+// Particle holds the basic components of the particle system.
+type Particle struct {
+	x, y        float64
+	px, py      float64
+	lx, ly      float64 // the position after the last full physics step, used for rendering
+	z, pz, lz   float64 // the depth, which is only moved by the wind of the 3D cloth
+	col, row    int
+	sheet       int // the index of the sheet of cloth the particle belongs to
+	mass        float64
+	vx, vy, vz  float64
+	elasticity  float64
+	dragForce   float64
+	pinX        bool
+	isActive    bool
+	rail        bool    // the particle is pinned up, but it can slide horizontally
+	railY       float64 // the vertical position of the sliding pin
+	asleep      bool    // the particle is at rest and it's not integrated until it gets disturbed
+	still       int     // the number of steps the particle has been at rest
+	clamped     bool    // the particle has been stopped by a wall or the floor in the last step
+	highlighted bool
+	color       color.NRGBA
+}
+
+// NewParticle initializes a new Particle.
+func NewParticle(x, y float64, col color.NRGBA) *Particle {
+	p := &Particle{
+		x: x, y: y, px: x, py: y, lx: x, ly: y, mass: 1, color: col,
+	}
+	p.isActive = true
+	p.highlighted = false
+	p.elasticity = 25.0
+	p.dragForce = mouseDragForce
+
+	return p
+}
+
+// Update updates the particle system using the Verlet integration.
+func (p *Particle) Update(cloth *Cloth, mouse *Mouse, width, height int, delta float64) {
+	p.update(cloth, mouse, width, height, delta)
+}
+
+// draw draws the particle at the {x, y} position with the radius `r`.
+func (p *Particle) draw(gtx layout.Context, x, y, r float32) {
+	var (
+		sq   float64
+		p1   f32.Point
+		p2   f32.Point
+		orig = f32.Pt(x-r, y)
+	)
+
+	sq = math.Sqrt(float64(r*r) - float64(r*r))
+	p1 = f32.Pt(x+float32(sq), y).Sub(orig)
+	p2 = f32.Pt(x-float32(sq), y).Sub(orig)
+
+	var path clip.Path
+	path.Begin(gtx.Ops)
+	path.Move(orig)
+	path.Arc(p1, p2, 2*math.Pi)
+	path.Close()
+
+	defer clip.Outline{Path: path.End()}.Op().Push(gtx.Ops).Pop()
+	paint.ColorOp{Color: p.color}.Add(gtx.Ops)
+	paint.PaintOp{}.Add(gtx.Ops)
+}
+
+// drag moves the particle along with the pointer `t`, limited by the particle elasticity.
+func (p *Particle) drag(t *Touch) {
+	dx := t.x - t.px
+	dy := t.y - t.py
+	if dx > p.elasticity {
+		dx = p.elasticity
+	}
+	if dy > p.elasticity {
+		dy = p.elasticity
+	}
+	if dx < -p.elasticity {
+		dx = -p.elasticity
+	}
+	if dy < -p.elasticity {
+		dy = -p.elasticity
+	}
+	p.px = p.x - fround(dx*p.dragForce)
+	p.py = p.y - fround(dy*p.dragForce)
+}
+
+// update is an internal method to update the cloth system using Verlet integration.
+func (p *Particle) update(cloth *Cloth, mouse *Mouse, width, height int, dt float64) {
+	p.highlighted, p.clamped = false, false
+
+	if p.pinX {
+		return
+	}
+
+	dx := p.x - mouse.x
+	dy := p.y - mouse.y
+	dist := distance(dx, dy)
+	// Dragging and cutting are affecting everything the cursor crossed since the last step.
+	swept := mouse.sweepDistance(p.x, p.y)
+
+	dragged := mouse.getDragging() && swept < clothTearDist
+	if dragged {
+		p.drag(&mouse.Touch)
+	}
+	// The additional touches are dragging the cloth the same way as the primary pointer.
+	for i := range mouse.touches {
+		if t := &mouse.touches[i]; t.sweepDistance(p.x, p.y) < clothTearDist {
+			p.drag(t)
+			dragged = true
+		}
+	}
+
+	// Modify the mouse focus area size on scrolling.
+	focusArea := mouse.getFocusArea()
+
+	if dist < focusArea {
+		p.highlighted = true
+	}
+
+	// With right click we can tear up the cloth at the mouse position.
+	if mouse.getRightButton() {
+		if p.isActive && swept < focusArea {
+			cloth.applyEdit(&tearEdit{p: p})
+		}
+	}
+
+	// Holding the left mouse button will increase the dragging force
+	// resulting in a much advanced cloth destruction.
+	if mouse.getLeftButton() {
+		p.increaseForce(mouse)
+	} else {
+		p.resetForce()
+	}
+
+	// The sleeping cloth is woken up only by dragging its particles or by the charged field.
+	fx, fy := cloth.fieldAt(mouse, p.x, p.y)
+	bx, by := blowerAt(mouse, p.x, p.y)
+	fx, fy = fx+bx, fy+by
+	if p.asleep {
+		if !dragged && fx == 0 && fy == 0 {
+			p.vx, p.vy, p.vz = 0, 0, 0
+			return
+		}
+		cloth.wake()
+	}
+
+	// The sliding pins are slowed down by the friction of the rail.
+	if p.rail {
+		p.px = p.x - fround((p.x-p.px)*(1-cloth.friction))
+	}
+
+	px, py := p.x, p.y
+	// The gravity accelerates every particle the same way, but the heavier particles are less affected by the wind.
+	wx, wy := cloth.windAt(p.x, p.y)
+	ax, ay, waterDrag := cloth.waterAt(p.x, p.y)
+	ax, ay = ax+fx, ay+fy
+	p.vx += cloth.forces.GravityX + (cloth.forces.WindX+wx+ax)/p.mass
+	p.vy += cloth.forces.GravityY + (cloth.forces.WindY+wy+ay)/p.mass
+
+	// velocity = acceleration * deltaTime
+	// position = velocity * deltaTime
+	posX, posY := fround(p.vx*fround(dt*dt)), fround(p.vy*fround(dt*dt))
+
+	// Verlet integration:
+	// x(t+Δt)=2x(t)−x(t−Δt)+a(t)Δt2
+	p.x = p.x + fround((p.x-p.px)*(1-cloth.dragX-waterDrag)) + posX
+	p.y = p.y + fround((p.y-p.py)*(1-cloth.dragY-waterDrag)) + posY
+
+	p.px, p.py = px, py
+
+	// The depth is integrated the same way, but without the boundaries.
+	if cloth.depth {
+		pz := p.z
+		p.vz += cloth.windDepthAt(p.x, p.y) / p.mass
+		p.z = p.z + fround((p.z-p.pz)*(1-cloth.dragX-waterDrag)) + fround(p.vz*fround(dt*dt))
+		p.pz = pz
+	}
+
+	// The floor is always there, but the walls can be removed letting the cloth swing off-screen.
+	if cloth.walls {
+		if p.x >= float64(width) {
+			p.bounce(cloth.floor, float64(width), true)
+		} else if p.x < 0 {
+			p.bounce(cloth.floor, 0, true)
+		}
+		if p.y < 0 {
+			p.bounce(cloth.floor, 0, false)
+		}
+	}
+	if p.y > float64(height) {
+		p.bounce(cloth.floor, float64(height), false)
+	}
+
+	p.vx, p.vy, p.vz = 0.0, 0.0, 0.0
+}
+
+// bounce places the particle on a window boundary at the `at` position, which is a vertical wall
+// or a horizontal floor or ceiling. The normal velocity is reflected by the restitution and the tangential
+// velocity is reduced by the friction, so the fallen pieces of the cloth are landing and crumpling.
+func (p *Particle) bounce(s Surface, at float64, vertical bool) {
+	p.clamped = true
+	vx, vy := p.x-p.px, p.y-p.py
+	if vertical {
+		p.x = at
+		p.px = at + fround(vx*s.Restitution)
+		p.py = p.y - fround(vy*(1-s.Friction))
+	} else {
+		p.y = at
+		p.py = at + fround(vy*s.Restitution)
+		p.px = p.x - fround(vx*(1-s.Friction))
+	}
+}
+
+// pin pins up or releases the particle. The released particle starts from rest.
+func (p *Particle) pin(pinned bool) {
+	p.pinX = pinned
+	p.px, p.py, p.pz = p.x, p.y, p.z
+}
+
+// position returns the particle position interpolated between the previous and the current
+// simulation step, where `alpha` is the fraction of the step time elapsed since the last step.
+// The Verlet position of the previous sub-step can't be used for this, since it's also
+// modified by the dragging and the boundary collisions.
+func (p *Particle) position(alpha float64) (x, y float64) {
+	if alpha >= 1 {
+		return p.x, p.y
+	}
+	return p.lx + (p.x-p.lx)*alpha, p.ly + (p.y-p.ly)*alpha
+}
+
+// depth returns the particle depth interpolated the same way as its position.
+func (p *Particle) depth(alpha float64) float64 {
+	if alpha >= 1 {
+		return p.z
+	}
+	return p.lz + (p.z-p.lz)*alpha
+}
+
+// invMass returns the inverse mass of the particle, which is zero for the pinned particles.
+func (p *Particle) invMass() float64 {
+	if p.pinX {
+		return 0
+	}
+	return 1 / p.mass
+}
+
+// increaseForce increases the dragging force.
+func (p *Particle) increaseForce(m *Mouse) {
+	p.dragForce += m.force
+	if p.dragForce > maxDragForce {
+		p.dragForce = maxDragForce
+	}
+}
+
+// resetForce resets the dragging force to the default value.
+func (p *Particle) resetForce() {
+	p.dragForce = mouseDragForce
+}
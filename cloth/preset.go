@@ -0,0 +1,328 @@
+package cloth
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// balloonPressure is the acceleration of the particles pushed out by the pressure of the inflated balloon.
+	balloonPressure = 4 * gravityForce
+	// balloonRadius is the radius of the balloon as a fraction of the cloth height.
+	balloonRadius = 0.4
+	// minBalloonArea is the smallest area of the squeezed balloon relative to its rest area,
+	// limiting the pressure building up inside it.
+	minBalloonArea = 0.1
+	// ropeCount is the number of the hanging ropes.
+	ropeCount = 7
+	// blobPressure is the acceleration of the particles pushed out by the pressure of the soft body.
+	blobPressure = 8 * gravityForce
+	// blobRadius is the radius of the soft body as a fraction of the cloth height.
+	blobRadius = 0.3
+	// sheetShift is the horizontal offset of the upper sheet as a fraction of the sheet width.
+	sheetShift = 0.3
+	// trampolineDrop is the height the ball is dropped from onto the trampoline.
+	trampolineDrop = 200
+	// trampolineLink is the length of the links of the trampoline bed. The short chain of long links
+	// stays taut even with a single solver iteration, where the fine grid would sag down to the floor.
+	trampolineLink = 40
+	// trampolineCompliance is the XPBD compliance of the trampoline bed, which gives way under the ball.
+	trampolineCompliance = 1e-5
+	// trampolineMass is the mass of the ball bouncing on the trampoline.
+	trampolineMass = 10
+)
+
+// Preset is a predefined scene with its own cloth construction and settings.
+type Preset struct {
+	Name        string
+	Description string
+	// Width and Height are the size of the cloth relative to the size of the widget.
+	Width, Height float64
+	// build creates the particles and the sticks of the cloth, where {x, y}
+	// is the position of the top-left side of the cloth. It defaults to the grid.
+	build func(c *Cloth, x, y int)
+	// setup changes the settings of the cloth when the preset is selected.
+	setup func(c *Cloth)
+	// isGrid is set for the presets built as a rectangular grid of particles.
+	isGrid bool
+}
+
+// PresetNames lists the presets in the order they are shown in the menus.
+var PresetNames = []string{"cloth", "flag", "net", "trampoline", "balloon", "blob", "rope", "sheets"}
+
+// presets holds the available presets by their names. The cloth preset is the default scene.
+var presets = map[string]*Preset{
+	"cloth": {
+		Name:        "cloth",
+		Description: "a rectangular piece of cloth pinned up at the top",
+		Width:       1.3,
+		Height:      0.4,
+		isGrid:      true,
+	},
+	"flag": {
+		Name:        "flag",
+		Description: "a flag pinned up to a pole at its left edge and rippling in the wind",
+		Width:       0.5,
+		Height:      0.4,
+		build:       buildFlag,
+		setup:       func(c *Cloth) { c.windModel.Enabled = true },
+		isGrid:      true,
+	},
+	"net": {
+		Name:        "net",
+		Description: "a net pinned up at all of its edges, stretched like a membrane over a frame",
+		Width:       0.6,
+		Height:      0.5,
+		build:       buildNet,
+		isGrid:      true,
+	},
+	"trampoline": {
+		Name:        "trampoline",
+		Description: "a taut strip pinned up at its ends, with a ball bouncing on it",
+		Width:       0.6,
+		Height:      0.03,
+		build:       buildTrampoline,
+		setup: func(c *Cloth) {
+			c.UseXPBD(true)
+			c.SetCompliance(trampolineCompliance)
+		},
+	},
+	"balloon": {
+		Name:        "balloon",
+		Description: "a closed loop of cloth inflated by its internal pressure",
+		Width:       1.3,
+		Height:      0.4,
+		build:       buildBalloon,
+	},
+	"blob": {
+		Name:        "blob",
+		Description: "a soft body falling freely, kept in shape by its internal pressure",
+		Width:       1.3,
+		Height:      0.4,
+		build:       buildBlob,
+	},
+	"sheets": {
+		Name:        "sheets",
+		Description: "a sheet of cloth falling onto another one and draping over it",
+		Width:       0.5,
+		Height:      0.3,
+		build:       buildSheets,
+	},
+	"rope": {
+		Name:        "rope",
+		Description: "a row of chains hanging from the top",
+		Width:       0.8,
+		Height:      0.4,
+		build:       buildRopes,
+	},
+}
+
+// presetKeys returns the set of the number keys switching between the presets.
+func presetKeys() string {
+	keys := make([]string, len(PresetNames))
+	for i := range PresetNames {
+		keys[i] = strconv.Itoa(i + 1)
+	}
+	return strings.Join(keys, "|")
+}
+
+// LookupPreset returns the preset with the given name.
+func LookupPreset(name string) (*Preset, error) {
+	p, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %q (available: %s)", name, strings.Join(PresetNames, ", "))
+	}
+	return p, nil
+}
+
+// SetPreset selects the preset building the cloth on the next initialization and applies its settings.
+func (c *Cloth) SetPreset(p *Preset) {
+	c.preset = p
+	if p != nil && p.setup != nil {
+		p.setup(c)
+	}
+}
+
+// grid reports whether the cloth is built by the preset as a rectangular grid of particles.
+func (p *Preset) grid() bool {
+	return p == nil || p.isGrid
+}
+
+// buildFlag builds the cloth grid pinned up only at its left column, like a flag on a pole.
+func buildFlag(c *Cloth, x, y int) {
+	c.initGrid(x, y)
+	for _, p := range c.particles {
+		p.pinX = p.col == 0
+	}
+}
+
+// buildRopes spreads a row of ropes over the cloth width, which are as long as the cloth height.
+// Every rope is a chain of particles pinned up at its top end.
+func buildRopes(c *Cloth, x, y int) {
+	links := c.height / c.spacing
+	gap := float64(c.width) / (ropeCount - 1)
+	spacing := float64(c.spacing)
+	for r := 0; r < ropeCount; r++ {
+		var prev *Particle
+		for i := 0; i <= links; i++ {
+			p := c.addParticle(float64(x)+fround(float64(r)*gap), float64(y+i*c.spacing), r, i)
+			if prev != nil {
+				c.connect(prev, p, spacing, stickStructural)
+			} else {
+				p.pinX = true
+			}
+			prev = p
+		}
+	}
+}
+
+// buildNet builds the cloth grid pinned up at all of its edges, so the inner part
+// of the cloth behaves like a membrane stretched over a frame.
+func buildNet(c *Cloth, x, y int) {
+	c.initGrid(x, y)
+	cols, rows := c.width/c.spacing, c.height/c.spacing
+	for _, p := range c.particles {
+		p.pinX = p.col == 0 || p.row == 0 || p.col == cols || p.row == rows
+	}
+}
+
+// buildTrampoline builds the side view of a trampoline: the bed is a chain of links pinned up at its
+// both ends to the frame, with a ball dropped onto the middle of the bed from above.
+func buildTrampoline(c *Cloth, x, y int) {
+	links := c.width / trampolineLink
+	var prev *Particle
+	for i := 0; i <= links; i++ {
+		p := c.addParticle(float64(x+i*trampolineLink), float64(y+trampolineDrop), i, 0)
+		p.pinX = i == 0 || i == links
+		if prev != nil {
+			c.connect(prev, p, trampolineLink, stickStructural)
+		}
+		prev = p
+	}
+	c.addBody(float64(x+links*trampolineLink/2), float64(y), bodyRadius, trampolineMass)
+}
+
+// buildSheets builds two separate sheets of cloth: the lower one is pinned up at the top,
+// while the upper one is falling freely, landing on the lower sheet and draping over it.
+func buildSheets(c *Cloth, x, y int) {
+	c.initGrid(x, y+c.height)
+	lower := len(c.particles)
+	c.initGrid(x+int(float64(c.width)*sheetShift), y)
+	for _, p := range c.particles[lower:] {
+		p.sheet = 1
+		p.pinX = false
+	}
+	c.sheets = 2
+}
+
+// Balloon is a closed loop of particles inflated by the pressure of the enclosed gas.
+// The loop particles are referenced by their index, so the balloon survives restoring the snapshots.
+type Balloon struct {
+	loop []int
+	// area is the rest area of the inflated balloon.
+	area float64
+	// pressure is the acceleration of the loop particles at the rest area.
+	pressure float64
+}
+
+// buildBalloon stitches the cloth into a ring of particles, which is tied up at the top like a balloon on a string.
+func buildBalloon(c *Cloth, x, y int) {
+	r := fround(float64(c.height) * balloonRadius)
+	b := c.addLoop(float64(x+c.width/2), float64(y)+r, r, balloonPressure)
+	c.particles[b.loop[0]].pinX = true
+}
+
+// buildBlob builds a soft body falling freely, which is kept in shape by its internal pressure
+// and by the bending sticks along its surface.
+func buildBlob(c *Cloth, x, y int) {
+	r := fround(float64(c.height) * blobRadius)
+	b := c.addLoop(float64(x+c.width/2), float64(y)+r, r, blobPressure)
+	for i, idx := range b.loop {
+		p1, p2 := c.particles[idx], c.particles[b.loop[(i+2)%len(b.loop)]]
+		c.connect(p1, p2, distance(p2.x-p1.x, p2.y-p1.y), stickBend)
+	}
+}
+
+// addLoop adds a closed loop of particles centered at {cx, cy} with the radius `r`,
+// which is inflated by the `pressure` acting on the loop particles.
+func (c *Cloth) addLoop(cx, cy, r, pressure float64) *Balloon {
+	n := int(2 * math.Pi * r / float64(c.spacing))
+	if n < 3 {
+		n = 3
+	}
+	b := &Balloon{pressure: pressure}
+	for i := 0; i < n; i++ {
+		// The loop starts at the top.
+		angle := 2*math.Pi*float64(i)/float64(n) - math.Pi/2
+		p := c.addParticle(cx+fround(r*cos(angle)), cy+fround(r*sin(angle)), i, 0)
+		b.loop = append(b.loop, len(c.particles)-1)
+		if i > 0 {
+			prev := c.particles[b.loop[i-1]]
+			c.connect(prev, p, distance(p.x-prev.x, p.y-prev.y), stickStructural)
+		}
+	}
+	first, last := c.particles[b.loop[0]], c.particles[b.loop[n-1]]
+	c.connect(last, first, distance(first.x-last.x, first.y-last.y), stickStructural)
+
+	b.area = b.currentArea(c)
+	c.balloon = b
+	return b
+}
+
+// currentArea returns the area enclosed by the balloon using the shoelace formula.
+func (b *Balloon) currentArea(c *Cloth) float64 {
+	var area float64
+	for i, idx := range b.loop {
+		p1, p2 := c.particles[idx], c.particles[b.loop[(i+1)%len(b.loop)]]
+		area += fround(p1.x*p2.y) - fround(p2.x*p1.y)
+	}
+	return area / 2
+}
+
+// sealed reports whether the balloon is still a closed loop. A single torn stick pops the balloon.
+func (b *Balloon) sealed(c *Cloth) bool {
+	sticks := make(map[[2]*Particle]bool, len(b.loop))
+	for _, ct := range c.constraints {
+		if ct.kind == stickStructural {
+			sticks[[2]*Particle{ct.p1, ct.p2}] = true
+		}
+	}
+	for i, idx := range b.loop {
+		p1, p2 := c.particles[idx], c.particles[b.loop[(i+1)%len(b.loop)]]
+		if !p1.isActive || !sticks[[2]*Particle{p1, p2}] {
+			return false
+		}
+	}
+	return true
+}
+
+// inflate pushes the loop particles outward along the normals of the loop edges. The pressure is inversely
+// proportional to the enclosed area, so the squeezed balloon pushes back harder. Once the balloon is popped
+// the pressure escapes and the loop collapses into a piece of cloth.
+func (b *Balloon) inflate(c *Cloth) {
+	if b == nil || !b.sealed(c) {
+		return
+	}
+	area := math.Max(b.currentArea(c), b.area*minBalloonArea)
+	pressure := fround(b.pressure * b.area / area / (2 * float64(c.spacing)))
+	for i, idx := range b.loop {
+		p1, p2 := c.particles[idx], c.particles[b.loop[(i+1)%len(b.loop)]]
+		// The outward normal of the edge, scaled by the edge length, is shared by its two particles.
+		nx, ny := fround((p2.y-p1.y)*pressure), fround((p1.x-p2.x)*pressure)
+		p1.push(nx, ny, 0)
+		p2.push(nx, ny, 0)
+	}
+}
+
+// push accelerates the particle by the force of the {fx, fy, fz} vector.
+func (p *Particle) push(fx, fy, fz float64) {
+	// The velocity of the pinned particles is never integrated, so it must not accumulate.
+	if p.pinX {
+		return
+	}
+	p.vx += fx / p.mass
+	p.vy += fy / p.mass
+	p.vz += fz / p.mass
+}
@@ -0,0 +1,43 @@
+package cloth
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+// TestTrampoline drops the ball onto the trampoline with a single and with many solver iterations,
+// and checks that the ball is caught by the bed above the floor and then thrown back up.
+func TestTrampoline(t *testing.T) {
+	const (
+		width, height = 940, 580
+		steps         = 600
+		delta         = 1.0 / 60
+		minBounce     = 20
+	)
+	for _, iterations := range []int{1, 8} {
+		c := NewCloth(564, 17, 8, 0.99, color.NRGBA{})
+		c.SetPreset(presets["trampoline"])
+		c.Init(188, 116)
+		c.SetIterations(iterations)
+
+		ball := c.particles[c.bodies[0].index]
+		bed := ball.y + trampolineDrop
+		// deepest is the lowest position of the ball, and bounce is how high it rose again from there.
+		deepest, bounce := ball.y, 0.0
+		for i := 0; i < steps; i++ {
+			c.keepPositions()
+			c.Step(&Mouse{}, width, height, delta)
+			if ball.y > deepest {
+				deepest = ball.y
+			}
+			bounce = math.Max(bounce, deepest-ball.y)
+		}
+		if deepest < bed-bodyRadius || deepest > height-2*bodyRadius {
+			t.Errorf("%d iterations: the lowest ball position %.0f is not on the bed at %.0f", iterations, deepest, bed)
+		}
+		if bounce < minBounce {
+			t.Errorf("%d iterations: the ball bounced up %.0f, want at least %d", iterations, bounce, minBounce)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package cloth
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// The render modes coloring the sticks of the cloth.
+const (
+	// renderPlain draws the sticks with the cloth color.
+	renderPlain = iota
+	// renderStrain colors the sticks by their strain.
+	renderStrain
+	// renderRainbow colors the sticks by their position, cycling through the hues over time.
+	renderRainbow
+	// renderGlow draws the sticks with a soft neon glow.
+	renderGlow
+)
+
+const (
+	// rainbowShades is the number of the hues the rainbow is split into.
+	// The sticks of the same hue are drawn as a single clip path.
+	rainbowShades = 32
+	// rainbowWavelength is the distance in pixels over which the rainbow goes through all the hues.
+	rainbowWavelength = 600
+	// rainbowPeriod is the time the rainbow takes to cycle through all the hues.
+	rainbowPeriod = 5 * time.Second
+	// tautStretch is the stretch ratio of the sticks drawn with the minimum line width.
+	tautStretch = 1.5
+	// glowPasses is the number of the glow strokes under the sticks, widening by glowSpread and fading out by glowFade.
+	glowPasses = 4
+	glowSpread = 3
+	glowFade   = 0.5
+	// glowOpacity is the opacity of the innermost glow stroke.
+	glowOpacity = 0.35
+)
+
+// renderModeNames lists the render modes by their values, in the order they are cycled through.
+var renderModeNames = []string{"plain", "strain", "rainbow", "glow"}
+
+// LookupRenderMode returns the render mode with the given name.
+func LookupRenderMode(name string) (int, error) {
+	for mode, n := range renderModeNames {
+		if n == name {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown render mode %q (available: %s)", name, strings.Join(renderModeNames, ", "))
+}
+
+// SetRenderMode sets the mode coloring the sticks. The strain mode colors them along the strain gradient.
+func (c *Cloth) SetRenderMode(mode int) {
+	c.render = mode
+}
+
+// RenderMode returns the mode coloring the sticks.
+func (c *Cloth) RenderMode() int {
+	return c.render
+}
+
+// SetTensionWidth draws the slack sticks with the `max` line width, thinning down to the `min` width as they
+// are stretched, showing how the load is distributed through the cloth. The zero `max` turns it off.
+func (c *Cloth) SetTensionWidth(min, max float64) {
+	c.minWidth, c.maxWidth = min, max
+}
+
+// addStick adds the outline of the stick to the path, which is thinner the more the stick is stretched
+// if the tension width is on.
+func (c *Cloth) addStick(path *clip.Path, ct *Constraint, alpha float64) {
+	if c.stroked() {
+		c.addStroke(path, ct, alpha)
+		return
+	}
+	if c.maxWidth <= 0 {
+		ct.addPath(path, alpha)
+		return
+	}
+	x1, y1 := ct.p1.position(alpha)
+	x2, y2 := ct.p2.position(alpha)
+	dist := distance(x2-x1, y2-y1)
+	if dist == 0 {
+		return
+	}
+	t := math.Max(0, math.Min((dist/ct.length-1)/(tautStretch-1), 1))
+	half := (c.maxWidth - (c.maxWidth-c.minWidth)*t) / 2
+	nx, ny := (y1-y2)/dist*half, (x2-x1)/dist*half
+
+	path.MoveTo(f32.Pt(float32(x1+nx), float32(y1+ny)))
+	path.LineTo(f32.Pt(float32(x2+nx), float32(y2+ny)))
+	path.LineTo(f32.Pt(float32(x2-nx), float32(y2-ny)))
+	path.LineTo(f32.Pt(float32(x1-nx), float32(y1-ny)))
+	path.Close()
+}
+
+// drawRainbow draws the sticks colored by the hue of the rainbow at their position,
+// which is shifting over time, so the colors are flowing across the cloth.
+func (c *Cloth) drawRainbow(gtx layout.Context, alpha float64) {
+	phase := float64(gtx.Now.UnixNano()%int64(rainbowPeriod)) / float64(rainbowPeriod)
+
+	var shades [rainbowShades]clip.Path
+	var used [rainbowShades]bool
+	for _, ct := range c.constraints {
+		if !ct.p1.isActive || ct.kind != stickStructural {
+			continue
+		}
+		x1, y1 := ct.p1.position(alpha)
+		x2, y2 := ct.p2.position(alpha)
+		hue := (x1+x2+y1+y2)/2/rainbowWavelength - phase
+		hue -= math.Floor(hue)
+		shade := int(hue*rainbowShades) % rainbowShades
+		if !used[shade] {
+			shades[shade].Begin(gtx.Ops)
+			used[shade] = true
+		}
+		c.addStick(&shades[shade], ct, alpha)
+	}
+	for shade := range shades {
+		if used[shade] {
+			col := HSLA{H: float32(shade) / rainbowShades, S: 0.9, L: 0.35, A: 1}.RGBA().SRGB()
+			c.fillSticks(gtx, col, &shades[shade])
+		}
+	}
+}
+
+// drawGlow draws the sticks with a soft glow, as a few translucent strokes getting wider and fainter
+// under the sticks. The overlapping strokes are adding up, so the dense parts of the cloth glow brighter.
+func (c *Cloth) drawGlow(gtx layout.Context, alpha float64) {
+	for pass := glowPasses; pass > 0; pass-- {
+		var path clip.Path
+		path.Begin(gtx.Ops)
+		for _, ct := range c.constraints {
+			if !ct.p1.isActive || ct.kind != stickStructural {
+				continue
+			}
+			x1, y1 := ct.p1.position(alpha)
+			x2, y2 := ct.p2.position(alpha)
+			path.MoveTo(f32.Pt(float32(x1), float32(y1)))
+			path.LineTo(f32.Pt(float32(x2), float32(y2)))
+		}
+		col := c.color
+		// The outermost stroke is the faintest one.
+		col.A = uint8(float64(col.A) * glowOpacity * math.Pow(glowFade, float64(pass-1)))
+		paint.FillShape(gtx.Ops, col, clip.Stroke{Path: path.End(), Width: float32(pass * glowSpread)}.Op())
+	}
+	var path clip.Path
+	path.Begin(gtx.Ops)
+	for _, ct := range c.constraints {
+		if ct.p1.isActive && ct.kind == stickStructural {
+			c.addStick(&path, ct, alpha)
+		}
+	}
+	c.fillSticks(gtx, c.color, &path)
+}
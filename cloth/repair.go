@@ -0,0 +1,93 @@
+package cloth
+
+import "math"
+
+// repairReach is the maximum distance between two neighbouring particles, relative to
+// the spacing, which can still be stitched together by the repair tool.
+const repairReach = 2.5
+
+// reviveEdit reactivates a particle torn off by the mouse.
+type reviveEdit struct {
+	p *Particle
+}
+
+func (e *reviveEdit) Apply(cloth *Cloth)  { e.p.isActive = true }
+func (e *reviveEdit) Revert(cloth *Cloth) { e.p.isActive = false }
+
+// stitchEdit adds a new stick to the cloth.
+type stitchEdit struct {
+	c *Constraint
+}
+
+func (e *stitchEdit) Apply(cloth *Cloth)  { cloth.constraints = append(cloth.constraints, e.c) }
+func (e *stitchEdit) Revert(cloth *Cloth) { e.c.removeConstraint(cloth) }
+
+// repairLink is the offset of a neighbouring particle in the grid, connected to the particle with a stick of the kind.
+type repairLink struct {
+	col, row int
+	kind     int
+	length   float64 // the rest length of the stick relative to the spacing
+}
+
+// repairLinks are the offsets of the neighbours connected like in Init: the structural sticks to the
+// adjacent particles, the shear sticks to the diagonal ones and the bending sticks to the second ones.
+var repairLinks = []repairLink{
+	{col: 1, kind: stickStructural, length: 1},
+	{row: 1, kind: stickStructural, length: 1},
+	{col: 1, row: 1, kind: stickShear, length: math.Sqrt2},
+	{col: -1, row: 1, kind: stickShear, length: math.Sqrt2},
+	{col: 2, kind: stickBend, length: 2},
+	{row: 2, kind: stickBend, length: 2},
+}
+
+// repair patches the holes inside the circle centered at {x, y} with the radius `r`.
+// The torn particles are reactivated and the missing sticks of every kind the cloth is built with are
+// recreated between the neighbouring particles of the grid, if they are still close enough to each other.
+func (c *Cloth) repair(x, y, r float64) {
+	cols := c.width/c.spacing + 1
+	rows := c.Rows()
+	spacing := float64(c.spacing)
+	// Only the rectangular grid of the cloth can be patched up.
+	if !c.preset.grid() {
+		return
+	}
+
+	linked := make(map[[2]*Particle]bool, len(c.constraints))
+	for _, ct := range c.constraints {
+		linked[[2]*Particle{ct.p1, ct.p2}] = true
+	}
+	// The sticks are connecting the earlier particle of the grid to the later one, like in Init.
+	stitch := func(p1, p2 *Particle, l repairLink) {
+		length := l.length * spacing
+		if linked[[2]*Particle{p1, p2}] || distance(p1.x-p2.x, p1.y-p2.y) > length*repairReach {
+			return
+		}
+		ct := NewConstraint(p1, p2, length, c.color)
+		ct.compliance = c.compliance
+		ct.kind = l.kind
+		ct.stiffness = c.stickStiffness(p1, p2)
+		c.applyEdit(&stitchEdit{c: ct})
+		linked[[2]*Particle{p1, p2}] = true
+	}
+
+	for _, p := range c.particles {
+		if distance(p.x-x, p.y-y) > r {
+			continue
+		}
+		if !p.isActive {
+			c.applyEdit(&reviveEdit{p: p})
+		}
+		for _, l := range repairLinks {
+			if (l.kind == stickShear && !c.shear) || (l.kind == stickBend && !c.bend) {
+				continue
+			}
+			// The particle is linked to the neighbours both before and after it.
+			if col, row := p.col-l.col, p.row-l.row; col >= 0 && col < cols && row >= 0 {
+				stitch(c.particles[row*cols+col], p, l)
+			}
+			if col, row := p.col+l.col, p.row+l.row; col >= 0 && col < cols && row < rows {
+				stitch(p, c.particles[row*cols+col], l)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package cloth
+
+// scrubFrames is the number of the frames the paused simulation is rewound or fast-forwarded by.
+const scrubFrames = 10
+
+// frameInput holds everything the simulation step depends on, besides the cloth state.
+type frameInput struct {
+	mouse    Mouse
+	settings settings
+	width    int
+	height   int
+	delta    float64
+}
+
+// Timeline counts the simulation steps and records the inputs of each step since the oldest snapshot.
+// Because the physics step is deterministic, any earlier frame can be reconstructed
+// by restoring the nearest snapshot and replaying the recorded inputs on top of it.
+type Timeline struct {
+	snapshots *Snapshots
+	inputs    []frameInput
+	start     int // the frame number of the first recorded input
+	frame     int
+}
+
+// NewTimeline creates a new timeline which snapshots are kept in a buffer of `size` states.
+func NewTimeline(size int) *Timeline {
+	return &Timeline{snapshots: NewSnapshots(size)}
+}
+
+// Step records the current input and advances the cloth simulation with one step.
+// Stepping from a rewound frame discards the recorded future.
+func (t *Timeline) Step(cloth *Cloth, mouse *Mouse, width, height int, delta float64) {
+	if t.frame < t.end() {
+		t.inputs = t.inputs[:t.frame-t.start]
+		t.snapshots.truncate(t.frame)
+	}
+	// Nothing to replay from without a snapshot, so there is no need to record the inputs.
+	if t.snapshots.oldest() == nil {
+		t.start = t.frame + 1
+	} else {
+		t.inputs = append(t.inputs, frameInput{
+			mouse: *mouse, settings: cloth.settings, width: width, height: height, delta: delta,
+		})
+	}
+	cloth.Step(mouse, width, height, delta)
+	t.frame++
+}
+
+// Advance moves the simulation one step forward. If the timeline has been rewound
+// the recorded input of the next frame is replayed, otherwise a new step is taken.
+func (t *Timeline) Advance(cloth *Cloth, mouse *Mouse, width, height int, delta float64) {
+	if t.frame < t.end() {
+		t.replay(cloth, t.frame, t.frame+1)
+		t.frame++
+		return
+	}
+	t.Step(cloth, mouse, width, height, delta)
+}
+
+// Capture takes a snapshot of the cloth at the current frame. It should also be called
+// after every change made outside of the simulation step (undo, reset etc.),
+// otherwise the replay would not be able to reproduce it.
+func (t *Timeline) Capture(cloth *Cloth) {
+	state := cloth.saveState()
+	state.frame = t.frame
+	t.snapshots.Capture(state)
+
+	// Drop the inputs preceding the oldest snapshot, since they can't be replayed anymore.
+	if oldest := t.snapshots.oldest(); oldest != nil && oldest.frame > t.start {
+		t.inputs = t.inputs[oldest.frame-t.start:]
+		t.start = oldest.frame
+	}
+}
+
+// Seek reconstructs the cloth state at the `target` frame by replaying the recorded
+// inputs from the nearest snapshot. The target is clamped to the recorded range.
+func (t *Timeline) Seek(cloth *Cloth, target int) {
+	oldest := t.snapshots.oldest()
+	if oldest == nil {
+		return
+	}
+	if target < oldest.frame {
+		target = oldest.frame
+	}
+	if target > t.end() {
+		target = t.end()
+	}
+	state := t.snapshots.nearest(target)
+	cloth.loadState(state)
+	t.replay(cloth, state.frame, target)
+	// The replayed edits are already part of the history which has been cleared on load.
+	cloth.history.Clear()
+	t.frame = target
+}
+
+// Back jumps to the previous snapshot.
+func (t *Timeline) Back(cloth *Cloth) {
+	if state := t.snapshots.Back(); state != nil {
+		cloth.loadState(state)
+		t.frame = state.frame
+	}
+}
+
+// Forward jumps to the next snapshot.
+func (t *Timeline) Forward(cloth *Cloth) {
+	if state := t.snapshots.Forward(); state != nil {
+		cloth.loadState(state)
+		t.frame = state.frame
+	}
+}
+
+// replay runs the recorded simulation steps between the `from` and `to` frames.
+// The live settings of the cloth (forces, wind, solver parameters) are preserved.
+func (t *Timeline) replay(cloth *Cloth, from, to int) {
+	live := cloth.settings
+	for f := from; f < to; f++ {
+		in := t.inputs[f-t.start]
+		cloth.settings = in.settings
+		cloth.Step(&in.mouse, in.width, in.height, in.delta)
+	}
+	cloth.settings = live
+}
+
+// end returns the frame number following the last recorded input.
+func (t *Timeline) end() int {
+	return t.start + len(t.inputs)
+}
@@ -0,0 +1,168 @@
+package cloth
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// Scheme is the color scheme of the cloth widget, covering the background, the cloth and the overlays.
+type Scheme struct {
+	// Background is the color behind the cloth, and Water is the one behind the underwater cloth.
+	Background color.NRGBA
+	Water      color.NRGBA
+	Cloth      color.NRGBA
+	// Text is the color of the status overlay.
+	Text color.NRGBA
+	// Foreground is the text color of the theme, used by the menu, the help and the host widgets.
+	Foreground color.NRGBA
+	// Panel is the background of the context menu and the help overlay.
+	Panel color.NRGBA
+}
+
+// Palette is a named pair of the light and the dark color scheme.
+type Palette struct {
+	Name  string
+	Light Scheme
+	Dark  Scheme
+}
+
+// paletteNames lists the palettes in the order they are cycled through.
+var paletteNames = []string{"classic", "neon", "pastel", "monochrome"}
+
+// palettes holds the available palettes by their names. The classic grey palette is the default.
+var palettes = map[string]*Palette{
+	"classic": {
+		Name: "classic",
+		Light: Scheme{
+			Background: color.NRGBA{R: 0xf2, G: 0xf2, B: 0xf2, A: 0xff},
+			Water:      color.NRGBA{R: 0xd4, G: 0xe8, B: 0xf0, A: 0xff},
+			Cloth:      color.NRGBA{R: 0x9a, G: 0x9a, B: 0x9a, A: 0xff},
+			Text:       color.NRGBA{R: 0x7f, A: 0xff},
+			Foreground: color.NRGBA{A: 0xff},
+			Panel:      color.NRGBA{R: 0xfa, G: 0xfa, B: 0xfa, A: 0xf0},
+		},
+		Dark: Scheme{
+			Background: color.NRGBA{R: 0x1e, G: 0x20, B: 0x24, A: 0xff},
+			Water:      color.NRGBA{R: 0x0e, G: 0x22, B: 0x2e, A: 0xff},
+			Cloth:      color.NRGBA{R: 0xb4, G: 0xb8, B: 0xbe, A: 0xff},
+			Text:       color.NRGBA{R: 0xff, G: 0x80, B: 0x70, A: 0xff},
+			Foreground: color.NRGBA{R: 0xe8, G: 0xe8, B: 0xe8, A: 0xff},
+			Panel:      color.NRGBA{R: 0x2a, G: 0x2c, B: 0x32, A: 0xf0},
+		},
+	},
+	"neon": {
+		Name: "neon",
+		Light: Scheme{
+			Background: color.NRGBA{R: 0xf4, G: 0xf0, B: 0xfa, A: 0xff},
+			Water:      color.NRGBA{R: 0xdc, G: 0xf4, B: 0xfa, A: 0xff},
+			Cloth:      color.NRGBA{R: 0xe0, G: 0x20, B: 0xc0, A: 0xff},
+			Text:       color.NRGBA{R: 0x90, B: 0xb0, A: 0xff},
+			Foreground: color.NRGBA{R: 0x20, G: 0x10, B: 0x30, A: 0xff},
+			Panel:      color.NRGBA{R: 0xfa, G: 0xf6, B: 0xff, A: 0xf0},
+		},
+		Dark: Scheme{
+			Background: color.NRGBA{R: 0x0a, G: 0x0a, B: 0x14, A: 0xff},
+			Water:      color.NRGBA{R: 0x04, G: 0x14, B: 0x24, A: 0xff},
+			Cloth:      color.NRGBA{R: 0x39, G: 0xff, B: 0x14, A: 0xff},
+			Text:       color.NRGBA{R: 0xff, G: 0x2d, B: 0xd2, A: 0xff},
+			Foreground: color.NRGBA{R: 0xe0, G: 0xff, B: 0xf0, A: 0xff},
+			Panel:      color.NRGBA{R: 0x16, G: 0x16, B: 0x28, A: 0xf0},
+		},
+	},
+	"pastel": {
+		Name: "pastel",
+		Light: Scheme{
+			Background: color.NRGBA{R: 0xfd, G: 0xf6, B: 0xf0, A: 0xff},
+			Water:      color.NRGBA{R: 0xdd, G: 0xee, B: 0xf6, A: 0xff},
+			Cloth:      color.NRGBA{R: 0xb8, G: 0xa4, B: 0xd8, A: 0xff},
+			Text:       color.NRGBA{R: 0xc0, G: 0x60, B: 0x80, A: 0xff},
+			Foreground: color.NRGBA{R: 0x50, G: 0x48, B: 0x58, A: 0xff},
+			Panel:      color.NRGBA{R: 0xff, G: 0xfa, B: 0xf4, A: 0xf0},
+		},
+		Dark: Scheme{
+			Background: color.NRGBA{R: 0x2e, G: 0x2a, B: 0x36, A: 0xff},
+			Water:      color.NRGBA{R: 0x22, G: 0x30, B: 0x3c, A: 0xff},
+			Cloth:      color.NRGBA{R: 0xf0, G: 0xc8, B: 0xd8, A: 0xff},
+			Text:       color.NRGBA{R: 0xa8, G: 0xe0, B: 0xc8, A: 0xff},
+			Foreground: color.NRGBA{R: 0xf0, G: 0xe8, B: 0xf0, A: 0xff},
+			Panel:      color.NRGBA{R: 0x3a, G: 0x36, B: 0x44, A: 0xf0},
+		},
+	},
+	"monochrome": {
+		Name: "monochrome",
+		Light: Scheme{
+			Background: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+			Water:      color.NRGBA{R: 0xe8, G: 0xe8, B: 0xe8, A: 0xff},
+			Cloth:      color.NRGBA{R: 0x20, G: 0x20, B: 0x20, A: 0xff},
+			Text:       color.NRGBA{A: 0xff},
+			Foreground: color.NRGBA{A: 0xff},
+			Panel:      color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xf0},
+		},
+		Dark: Scheme{
+			Background: color.NRGBA{A: 0xff},
+			Water:      color.NRGBA{R: 0x18, G: 0x18, B: 0x18, A: 0xff},
+			Cloth:      color.NRGBA{R: 0xf0, G: 0xf0, B: 0xf0, A: 0xff},
+			Text:       color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+			Foreground: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+			Panel:      color.NRGBA{R: 0x10, G: 0x10, B: 0x10, A: 0xf0},
+		},
+	},
+}
+
+// LookupPalette returns the palette with the given name.
+func LookupPalette(name string) (*Palette, error) {
+	p, ok := palettes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown palette %q (available: %s)", name, strings.Join(paletteNames, ", "))
+	}
+	return p, nil
+}
+
+// SetColor sets the color of the cloth sticks.
+func (c *Cloth) SetColor(col color.NRGBA) {
+	c.color = col
+}
+
+// SetDarkMode switches between the light and the dark scheme of the palette.
+func (w *ClothWidget) SetDarkMode(dark bool) {
+	w.dark = dark
+	w.applyScheme()
+}
+
+// SetPalette switches to the palette, keeping the light or the dark scheme.
+func (w *ClothWidget) SetPalette(p *Palette) {
+	w.palette = p
+	w.applyScheme()
+}
+
+// cyclePalette switches to the next palette.
+func (w *ClothWidget) cyclePalette() {
+	for i, name := range paletteNames {
+		if name == w.palette.Name {
+			w.SetPalette(palettes[paletteNames[(i+1)%len(paletteNames)]])
+			return
+		}
+	}
+}
+
+// applyScheme applies the current scheme of the palette to the cloth and the theme,
+// so the overlays and the host widgets sharing the theme adapt too.
+func (w *ClothWidget) applyScheme() {
+	w.scheme = w.palette.Light
+	if w.dark {
+		w.scheme = w.palette.Dark
+	}
+	if w.cloth != nil {
+		w.cloth.SetColor(w.scheme.Cloth)
+	}
+	if w.Theme != nil {
+		w.Theme.Palette.Fg = w.scheme.Foreground
+		w.Theme.Palette.Bg = w.scheme.Background
+	}
+}
+
+// DarkMode reports whether the dark color scheme is used.
+func (w *ClothWidget) DarkMode() bool {
+	return w.dark
+}
@@ -0,0 +1,119 @@
+package cloth
+
+import (
+	"image/color"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// The group operations applied to the selected particles.
+const (
+	// groupDelete tears out the selected particles.
+	groupDelete = iota
+	// groupPin pins up the selected particles.
+	groupPin
+	// groupUnpin releases the selected particles.
+	groupUnpin
+	// groupMass makes the selected particles heavier.
+	groupMass
+	// groupPush pushes the selected particles upward.
+	groupPush
+)
+
+const (
+	// groupMassScale is the factor the mass of the selected particles is multiplied with.
+	groupMassScale = 2
+	// groupImpulse is the velocity in pixels per step given to the selected particles by the push.
+	groupImpulse = 20
+)
+
+// selectionColor is the color of the lasso and the selected particles.
+var selectionColor = color.NRGBA{R: 0x39, G: 0x8d, B: 0xd9, A: 0xc0}
+
+// selectInside returns the indices of the active cloth particles inside the `lasso` polygon.
+func (c *Cloth) selectInside(lasso []f32.Point) []int {
+	var selected []int
+	for i, p := range c.particles {
+		if p.isActive && p.col >= 0 && inside(lasso, float32(p.x), float32(p.y)) {
+			selected = append(selected, i)
+		}
+	}
+	return selected
+}
+
+// editGroup applies the group operation `op` on the particles at the `selected` indices.
+// Deleting and pinning are recorded into the undo history, like the edits of the single particles.
+func (c *Cloth) editGroup(selected []int, op int) {
+	for _, i := range selected {
+		if i >= len(c.particles) {
+			continue
+		}
+		p := c.particles[i]
+		switch op {
+		case groupDelete:
+			if p.isActive {
+				c.applyEdit(&tearEdit{p: p})
+			}
+		case groupPin, groupUnpin:
+			if pinned := op == groupPin; p.pinX != pinned {
+				c.applyEdit(&pinEdit{p: p, pinned: pinned})
+			}
+		case groupMass:
+			p.mass *= groupMassScale
+		case groupPush:
+			if !p.pinX {
+				p.py += groupImpulse
+			}
+		}
+	}
+	c.history.Commit()
+	c.wake()
+}
+
+// inside reports whether the {x, y} point is inside the polygon, using the even-odd rule.
+func inside(poly []f32.Point, x, y float32) bool {
+	in := false
+	for i, j := 0, len(poly)-1; i < len(poly); j, i = i, i+1 {
+		a, b := poly[i], poly[j]
+		if (a.Y > y) != (b.Y > y) && x < a.X+(y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			in = !in
+		}
+	}
+	return in
+}
+
+// editSelection applies the group operation `op` on the selected particles.
+func (w *ClothWidget) editSelection(op int) {
+	w.cloth.editGroup(w.selection, op)
+	if op == groupDelete {
+		w.selection = nil
+	}
+	w.timeline.Capture(w.cloth)
+}
+
+// drawSelection draws the lasso being drawn and marks the selected particles.
+func (w *ClothWidget) drawSelection(gtx layout.Context) {
+	if len(w.lasso) > 1 {
+		var path clip.Path
+		path.Begin(gtx.Ops)
+		path.MoveTo(w.lasso[0])
+		for _, pt := range w.lasso[1:] {
+			path.LineTo(pt)
+		}
+		path.Close()
+		paint.FillShape(gtx.Ops, selectionColor, clip.Stroke{Path: path.End(), Width: 1.5}.Op())
+	}
+	for _, i := range w.selection {
+		if i >= len(w.cloth.particles) {
+			continue
+		}
+		if p := w.cloth.particles[i]; p.isActive {
+			x, y := p.position(1)
+			rect := clip.Rect{Min: f32.Pt(float32(x)-2, float32(y)-2).Round(), Max: f32.Pt(float32(x)+2, float32(y)+2).Round()}
+			paint.FillShape(gtx.Ops, selectionColor, rect.Op())
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cloth
+
+import (
+	"image/color"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+const (
+	// shadowSquash is the vertical scale of the cloth projected onto the floor.
+	shadowSquash = 0.2
+	// shadowSkew is the horizontal displacement of the projected cloth per its height above the floor.
+	shadowSkew = 0.35
+	// shadowLayers is the number of the strokes, widening by shadowSpread, which are blurring the shadow.
+	shadowLayers = 4
+	shadowSpread = 6
+)
+
+// shadowColor is the color of a single layer of the shadow. The layers are adding up toward the middle of the shadow.
+var shadowColor = color.NRGBA{A: 0x18}
+
+// SetShadow turns on or off the soft shadow cast by the cloth onto the floor.
+func (c *Cloth) SetShadow(on bool) {
+	c.shadow = on
+}
+
+// Shadow reports whether the cloth casts a shadow.
+func (c *Cloth) Shadow() bool {
+	return c.shadow
+}
+
+// drawShadow projects the sticks onto the floor at the bottom of the area, as they were lit
+// from the top left, and draws them as a few translucent strokes of growing width, softening its edges.
+func (c *Cloth) drawShadow(gtx layout.Context, alpha float64) {
+	floor := float32(gtx.Constraints.Max.Y)
+	// x' = x + skew*(floor-y), y' = floor - squash*(floor-y)
+	proj := f32.NewAffine2D(1, -shadowSkew, shadowSkew*floor, 0, shadowSquash, floor*(1-shadowSquash))
+	defer op.Affine(proj).Push(gtx.Ops).Pop()
+
+	for layer := 1; layer <= shadowLayers; layer++ {
+		var path clip.Path
+		path.Begin(gtx.Ops)
+		for _, ct := range c.constraints {
+			if !ct.p1.isActive || ct.kind != stickStructural {
+				continue
+			}
+			x1, y1 := ct.p1.position(alpha)
+			x2, y2 := ct.p2.position(alpha)
+			path.MoveTo(f32.Pt(float32(x1), float32(y1)))
+			path.LineTo(f32.Pt(float32(x2), float32(y2)))
+		}
+		paint.FillShape(gtx.Ops, shadowColor, clip.Stroke{Path: path.End(), Width: float32(layer * shadowSpread)}.Op())
+	}
+}
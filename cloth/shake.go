@@ -0,0 +1,17 @@
+package cloth
+
+// shakeImpulse is the velocity in pixels per step given to the cloth by the shake key.
+const shakeImpulse = 20
+
+// Shake gives every free particle the same {vx, vy} velocity change, as the inertia of the cloth would do
+// if its frame has been jerked in the opposite direction, while the pinned particles are moving along with the frame.
+// Gio doesn't report the window position, so the moving window can't shake the cloth directly.
+func (c *Cloth) Shake(vx, vy float64) {
+	for _, p := range c.particles {
+		if p.isActive && !p.pinX {
+			p.px -= vx
+			p.py -= vy
+		}
+	}
+	c.wake()
+}
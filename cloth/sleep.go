@@ -0,0 +1,105 @@
+package cloth
+
+import "math"
+
+const (
+	// sleepMotion is the largest particle displacement in a step, under which the particle is considered at rest.
+	sleepMotion = idleMotion
+	// sleepSteps is the number of steps every particle must be at rest before the cloth falls asleep.
+	sleepSteps = 60
+)
+
+// sleepKey holds the settings which are moving the cloth as a whole. Changing any of them wakes up every particle.
+type sleepKey struct {
+	forces     Forces
+	windModel  WindModel
+	underwater bool
+	ball       Circle
+	ballOn     bool
+	obstacles  int
+}
+
+// SetSleeping turns on or off putting the resting particles to sleep. The sleeping particles
+// are not integrated and the sticks between them are not solved, until they get disturbed.
+func (c *Cloth) SetSleeping(on bool) {
+	c.sleeping = on
+	if !on {
+		c.wake()
+	}
+}
+
+// wake wakes up every sleeping particle.
+func (c *Cloth) wake() {
+	for _, p := range c.particles {
+		p.asleep, p.still = false, 0
+	}
+}
+
+// Asleep reports whether every free particle is asleep, so the cloth isn't moving until it gets disturbed.
+func (c *Cloth) Asleep() bool {
+	if !c.sleeping {
+		return false
+	}
+	for _, p := range c.particles {
+		if p.isActive && !p.pinX && !p.asleep {
+			return false
+		}
+	}
+	return true
+}
+
+// resting reports whether the particle is not moved by the integration.
+func (p *Particle) resting() bool {
+	return p.asleep || p.pinX
+}
+
+// settle puts the cloth to sleep once every free particle has been at rest for long enough, and wakes it up
+// when a sleeping particle gets moved by the solver, e.g. pulled by a dragged neighbour or pushed by a collision.
+// The particles are put to sleep only all together: the stretched sticks are balancing the gravity in every step,
+// so a sleeping particle between awake neighbours would be yanked by its sticks once woken up.
+func (c *Cloth) settle() {
+	key := sleepKey{
+		forces:     c.forces,
+		windModel:  c.windModel,
+		underwater: c.underwater,
+		ball:       c.ball,
+		ballOn:     c.ballOn,
+		obstacles:  len(c.obstacles),
+	}
+	if key != c.sleepKey {
+		c.sleepKey = key
+		c.wake()
+		return
+	}
+	still := true
+	for _, p := range c.particles {
+		if !p.isActive || p.pinX {
+			continue
+		}
+		moved := math.Max(math.Abs(p.x-p.px), math.Max(math.Abs(p.y-p.py), math.Abs(p.z-p.pz)))
+		if p.asleep {
+			// The sleeping particles are keeping their previous position, so any displacement is a disturbance.
+			if moved > 0 {
+				c.wake()
+				return
+			}
+			continue
+		}
+		// The particles held by a wall or the floor are at rest, even though the bounce keeps moving their previous position.
+		if moved >= sleepMotion && !p.clamped {
+			p.still = 0
+		} else {
+			p.still++
+		}
+		still = still && p.still >= sleepSteps
+	}
+	if !still {
+		return
+	}
+	for _, p := range c.particles {
+		if p.isActive && !p.pinX {
+			p.asleep = true
+			p.px, p.py, p.pz = p.x, p.y, p.z
+		}
+	}
+}
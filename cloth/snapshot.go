@@ -0,0 +1,162 @@
+package cloth
+
+import (
+	"image"
+	"image/color"
+)
+
+// clothState holds the full state of the cloth, where the constraints are
+// referencing the particles by their index instead of their memory address.
+// This way the state can be stored and restored independently of the live cloth.
+type clothState struct {
+	frame       int
+	simTime     float64
+	sleepKey    sleepKey
+	origin      image.Point
+	particles   []Particle
+	constraints []constraintState
+	bodies      []Body
+}
+
+type constraintState struct {
+	p1, p2     int
+	length     float64
+	color      color.NRGBA
+	compliance float64
+	kind       int
+	initial    float64
+	stiffness  float64
+}
+
+// saveState captures the current state of the cloth.
+func (c *Cloth) saveState() *clothState {
+	index := make(map[*Particle]int, len(c.particles))
+	state := &clothState{
+		simTime:     c.simTime,
+		sleepKey:    c.sleepKey,
+		origin:      c.origin,
+		particles:   make([]Particle, len(c.particles)),
+		constraints: make([]constraintState, len(c.constraints)),
+	}
+	for i, p := range c.particles {
+		index[p] = i
+		state.particles[i] = *p
+	}
+	for _, b := range c.bodies {
+		state.bodies = append(state.bodies, *b)
+	}
+	for i, ct := range c.constraints {
+		state.constraints[i] = constraintState{
+			p1:         index[ct.p1],
+			p2:         index[ct.p2],
+			length:     ct.length,
+			color:      ct.color,
+			compliance: ct.compliance,
+			kind:       ct.kind,
+			initial:    ct.initial,
+			stiffness:  ct.stiffness,
+		}
+	}
+	return state
+}
+
+// loadState restores the positions, velocities and the topology of the cloth from a previously saved state.
+// Because the particles are recreated the undo history is no longer valid, so it gets cleared.
+func (c *Cloth) loadState(state *clothState) {
+	c.simTime, c.sleepKey, c.origin = state.simTime, state.sleepKey, state.origin
+	// The dangling threads are attached to the replaced particles.
+	c.frays = nil
+	c.particles = make([]*Particle, len(state.particles))
+	for i := range state.particles {
+		p := state.particles[i]
+		// Don't interpolate from the position before the jump.
+		p.lx, p.ly, p.lz = p.x, p.y, p.z
+		c.particles[i] = &p
+	}
+	c.constraints = make([]*Constraint, len(state.constraints))
+	for i, cs := range state.constraints {
+		c.constraints[i] = NewConstraint(c.particles[cs.p1], c.particles[cs.p2], cs.length, cs.color)
+		c.constraints[i].compliance = cs.compliance
+		c.constraints[i].kind = cs.kind
+		c.constraints[i].initial = cs.initial
+		c.constraints[i].stiffness = cs.stiffness
+	}
+	c.bodies = make([]*Body, len(state.bodies))
+	for i := range state.bodies {
+		b := state.bodies[i]
+		c.bodies[i] = &b
+	}
+	c.history.Clear()
+}
+
+// Snapshots is a ring buffer of cloth states ordered by their frame number,
+// which can be navigated back and forth.
+type Snapshots struct {
+	states []*clothState
+	size   int
+	cursor int
+}
+
+// NewSnapshots creates a new snapshot buffer holding at most `size` states.
+func NewSnapshots(size int) *Snapshots {
+	return &Snapshots{size: size, cursor: -1}
+}
+
+// Capture stores a cloth state into the buffer. If the buffer is full the oldest snapshot is dropped.
+func (s *Snapshots) Capture(state *clothState) {
+	if s.size <= 0 {
+		return
+	}
+	s.truncate(state.frame - 1)
+	s.states = append(s.states, state)
+	if len(s.states) > s.size {
+		s.states = s.states[len(s.states)-s.size:]
+	}
+	s.cursor = len(s.states) - 1
+}
+
+// Back returns the snapshot preceding the current one.
+func (s *Snapshots) Back() *clothState {
+	if s.cursor <= 0 {
+		return nil
+	}
+	s.cursor--
+	return s.states[s.cursor]
+}
+
+// Forward returns the snapshot following the current one.
+func (s *Snapshots) Forward() *clothState {
+	if s.cursor >= len(s.states)-1 {
+		return nil
+	}
+	s.cursor++
+	return s.states[s.cursor]
+}
+
+// oldest returns the oldest snapshot from the buffer.
+func (s *Snapshots) oldest() *clothState {
+	if len(s.states) == 0 {
+		return nil
+	}
+	return s.states[0]
+}
+
+// nearest returns the latest snapshot which is not newer than `frame`.
+func (s *Snapshots) nearest(frame int) *clothState {
+	for i := len(s.states) - 1; i >= 0; i-- {
+		if s.states[i].frame <= frame {
+			return s.states[i]
+		}
+	}
+	return nil
+}
+
+// truncate discards the snapshots newer than `frame`.
+func (s *Snapshots) truncate(frame int) {
+	for len(s.states) > 0 && s.states[len(s.states)-1].frame > frame {
+		s.states = s.states[:len(s.states)-1]
+	}
+	if s.cursor > len(s.states)-1 {
+		s.cursor = len(s.states) - 1
+	}
+}
@@ -0,0 +1,57 @@
+package cloth
+
+const (
+	// modelVerlet keeps the sticks at their length with the position-based constraint solver.
+	modelVerlet = iota
+	// modelSpring pulls the sticks back to their length with the damped spring forces.
+	modelSpring
+)
+
+// UseSprings switches between the position-based constraints and the classic mass-spring-damper model,
+// where every stick is a spring with the `k` stiffness and the `damping` coefficient.
+// The spring forces are integrated explicitly, so the stiff springs need more sub-steps to stay stable.
+func (c *Cloth) UseSprings(on bool, k, damping float64) {
+	c.model = modelVerlet
+	if on {
+		c.model = modelSpring
+	}
+	c.springK, c.springDamp = k, damping
+}
+
+// spring applies the force of the damped spring between the stick end points.
+func (c *Constraint) spring(cloth *Cloth, mouse *Mouse, delta float64) {
+	dx := c.p1.x - c.p2.x
+	dy := c.p1.y - c.p2.y
+	dz := c.p1.z - c.p2.z
+	dist := distance3(dx, dy, dz)
+	grain := c.grain(cloth)
+	tearDist := cloth.tearDist * grain.Tear * c.stiffness
+	c.strain = dist / tearDist
+
+	if dist < c.length && c.kind == stickStructural || dist == 0 {
+		return
+	}
+	if mouse.tearing() && dist > tearDist {
+		cloth.tear(c)
+		return
+	}
+
+	k := cloth.springK * grain.Stiffness * c.stiffness
+	switch c.kind {
+	case stickShear:
+		k *= shearStiffness
+	case stickBend:
+		k *= bendStiffness
+	}
+	nx, ny, nz := dx/dist, dy/dist, dz/dist
+	// The damping is resisting the relative velocity of the end points along the spring.
+	vx := (c.p1.x - c.p1.px) - (c.p2.x - c.p2.px)
+	vy := (c.p1.y - c.p1.py) - (c.p2.y - c.p2.py)
+	vz := (c.p1.z - c.p1.pz) - (c.p2.z - c.p2.pz)
+	speed := (fround(vx*nx) + fround(vy*ny) + fround(vz*nz)) / delta
+	force := fround(k*(dist-c.length)) + fround(cloth.springDamp*speed)
+
+	fx, fy, fz := fround(nx*force), fround(ny*force), fround(nz*force)
+	c.p1.push(-fx, -fy, -fz)
+	c.p2.push(fx, fy, fz)
+}
@@ -0,0 +1,59 @@
+package cloth
+
+import "math"
+
+// Stats holds the energy and stability diagnostics of the last physics step,
+// which are used for evaluating the solver changes.
+type Stats struct {
+	// Kinetic is the total kinetic energy of the particles.
+	Kinetic float64
+	// Potential is the total gravitational potential energy of the particles, relative to the floor.
+	Potential float64
+	// MaxStrain is the largest relative stretch of the structural sticks.
+	MaxStrain float64
+	// Residual is the mean relative length error of the sticks left after the solver iterations.
+	Residual float64
+}
+
+// SetDiagnostics turns the collection of the step diagnostics on or off.
+func (c *Cloth) SetDiagnostics(on bool) {
+	c.diagnose = on
+}
+
+// Stats returns the diagnostics of the last physics step. They are collected only if the diagnostics are on.
+func (c *Cloth) Stats() Stats {
+	return c.stats
+}
+
+// measure collects the diagnostics of the physics step, where `height`
+// is the position of the floor and `delta` is the time step.
+func (c *Cloth) measure(height int, delta float64) {
+	var s Stats
+	for _, p := range c.particles {
+		if !p.isActive || p.pinX {
+			continue
+		}
+		vx, vy := (p.x-p.px)/delta, (p.y-p.py)/delta
+		s.Kinetic += 0.5 * p.mass * (vx*vx + vy*vy)
+		s.Potential += p.mass * (c.forces.GravityY*(float64(height)-p.y) - c.forces.GravityX*p.x)
+	}
+	var sticks int
+	for _, ct := range c.constraints {
+		if !ct.p1.isActive {
+			continue
+		}
+		dist := distance(ct.p1.x-ct.p2.x, ct.p1.y-ct.p2.y)
+		err := (dist - ct.length) / ct.length
+		// The structural sticks are not resisting the compression, so it's not an error.
+		if ct.kind == stickStructural {
+			err = math.Max(err, 0)
+			s.MaxStrain = math.Max(s.MaxStrain, err)
+		}
+		s.Residual += math.Abs(err)
+		sticks++
+	}
+	if sticks > 0 {
+		s.Residual /= float64(sticks)
+	}
+	c.stats = s
+}
@@ -0,0 +1,97 @@
+package cloth
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// physicsDelta is the initial time step of the simulation, when it's not running at a fixed rate.
+	physicsDelta = 0.015
+	// minFrameDelta and maxFrameDelta are limiting the measured frame time used as the time step,
+	// so that a stalled frame can't make the physics explode with a huge step.
+	minFrameDelta = 1.0 / 240
+	maxFrameDelta = 1.0 / 30
+	// deltaSmoothing is the weight of the last frame time in the smoothed time step.
+	deltaSmoothing = 0.1
+	// physicsRate is the default fixed rate of the simulation in steps per second.
+	physicsRate = 60
+	// maxFrameSteps limits the number of steps taken in a single frame, so that
+	// a stalled frame doesn't trigger an ever increasing number of catch-up steps.
+	maxFrameSteps = 8
+)
+
+// Stepper converts the elapsed frame time into fixed length physics steps.
+// The time left over after the last step is kept in the accumulator and its fraction
+// of a step is used for interpolating the particle positions between two steps.
+type Stepper struct {
+	delta  float64
+	acc    float64
+	last   time.Time
+	smooth float64 // the smoothed frame time, when stepping once per frame
+	scale  float64 // the speed of the simulated time relative to the real time
+}
+
+// NewStepper creates a new stepper running the physics at `hz` steps per second.
+// With a zero rate one step is taken on every frame, which length is the measured frame time,
+// clamped and smoothed to keep the simulation stable on slow machines.
+func NewStepper(hz float64) *Stepper {
+	s := &Stepper{smooth: physicsDelta, scale: 1}
+	if hz > 0 {
+		s.delta = 1 / hz
+	}
+	return s
+}
+
+// Advance returns the number of physics steps to run for the frame drawn at `now`,
+// the length of a step and the interpolation factor between the last two steps.
+func (s *Stepper) Advance(now time.Time) (steps int, delta, alpha float64) {
+	if s.delta == 0 {
+		if !s.last.IsZero() {
+			elapsed := math.Max(minFrameDelta, math.Min(now.Sub(s.last).Seconds(), maxFrameDelta))
+			s.smooth += (elapsed - s.smooth) * deltaSmoothing
+		}
+		s.last = now
+		if s.scale == 1 {
+			return 1, s.smooth, 1
+		}
+		// The slowed down simulation is stepping only on every few frames.
+		s.acc += s.scale
+		steps = int(s.acc)
+		s.acc -= float64(steps)
+		return steps, s.smooth, s.acc
+	}
+	if !s.last.IsZero() {
+		s.acc += now.Sub(s.last).Seconds() * s.scale
+	}
+	s.last = now
+	if max := maxFrameSteps * s.delta; s.acc > max {
+		s.acc = max
+	}
+	for s.acc >= s.delta {
+		s.acc -= s.delta
+		steps++
+	}
+	return steps, s.delta, s.acc / s.delta
+}
+
+// Pause resets the frame timer, so that the time elapsed
+// while the simulation has been paused is not simulated.
+func (s *Stepper) Pause() {
+	s.last = time.Time{}
+	s.acc = 0
+}
+
+// SetScale sets the speed of the simulated time relative to the real time. The length of the steps
+// is not changed, only fewer steps are taken, so the slowed down cloth moves exactly the same way.
+func (s *Stepper) SetScale(scale float64) {
+	s.scale = scale
+}
+
+// Delta returns the length of a physics step.
+func (s *Stepper) Delta() float64 {
+	if s.delta == 0 {
+		return s.smooth
+	}
+	return s.delta
+}
@@ -0,0 +1,86 @@
+package cloth
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+)
+
+const (
+	// strainViewRange is the relative length error of the sticks shown with the end color of the strain view.
+	strainViewRange = 0.25
+	// legendWidth and legendHeight are the size of the gradient bar of the strain view legend.
+	legendWidth  = 160
+	legendHeight = 10
+)
+
+// drawStrainView draws every stick, including the shear and the bending ones, colored by its relative
+// length error normalized to the strain view range, regardless of the render mode. The stretched
+// and the compressed sticks are colored the same way, since both are errors left by the solver.
+// The flat view wouldn't line up with the cloth seen in perspective, so it's not drawn over the 3D cloth.
+func (c *Cloth) drawStrainView(gtx layout.Context, gradient Gradient, alpha float64) {
+	if c.depth {
+		return
+	}
+	var shades [strainShades]clip.Path
+	var used [strainShades]bool
+	for _, ct := range c.constraints {
+		if !ct.p1.isActive {
+			continue
+		}
+		x1, y1 := ct.p1.position(alpha)
+		x2, y2 := ct.p2.position(alpha)
+		err := math.Abs(distance3(x2-x1, y2-y1, ct.p2.depth(alpha)-ct.p1.depth(alpha))-ct.length) / ct.length
+		shade := int(math.Min(err/strainViewRange, 1) * (strainShades - 1))
+		if !used[shade] {
+			shades[shade].Begin(gtx.Ops)
+			used[shade] = true
+		}
+		ct.addPath(&shades[shade], alpha)
+	}
+	for shade := range shades {
+		if used[shade] {
+			col := gradient.At(float64(shade) / (strainShades - 1))
+			paint.FillShape(gtx.Ops, col, clip.Outline{Path: shades[shade].End()}.Op())
+		}
+	}
+}
+
+// drawLegend draws the legend of the strain view in the bottom left corner.
+func (w *ClothWidget) drawLegend(gtx layout.Context) {
+	if !w.strainView || w.Theme == nil || w.cloth.depth {
+		return
+	}
+	macro := op.Record(gtx.Ops)
+	gtx.Constraints.Min = image.Point{}
+	dims := layout.UniformInset(unit.Dp(8)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(material.Caption(w.Theme, "Stick length error").Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				size := image.Pt(gtx.Dp(legendWidth), gtx.Dp(legendHeight))
+				w.config.Gradient.paint(gtx, image.Rectangle{Max: size}, true)
+				return layout.Dimensions{Size: size}
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min.X = gtx.Dp(legendWidth)
+				gtx.Constraints.Max.X = gtx.Constraints.Min.X
+				return layout.Flex{Spacing: layout.SpaceBetween}.Layout(gtx,
+					layout.Rigid(material.Caption(w.Theme, "0%").Layout),
+					layout.Rigid(material.Caption(w.Theme, fmt.Sprintf("%.0f%%+", strainViewRange*100)).Layout),
+				)
+			}),
+		)
+	})
+	call := macro.Stop()
+
+	defer op.Offset(image.Pt(0, w.size.Y-dims.Size.Y)).Push(gtx.Ops).Pop()
+	paint.FillShape(gtx.Ops, w.scheme.Panel, clip.Rect{Max: dims.Size}.Op())
+	call.Add(gtx.Ops)
+}
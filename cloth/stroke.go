@@ -0,0 +1,76 @@
+package cloth
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// The cap styles of the stroked sticks.
+const (
+	// capButt ends the stroke exactly at the particle.
+	capButt = iota
+	// capSquare extends the stroke past the particle by half of its width.
+	capSquare
+	// capRound ends the stroke with a disc around the particle, which is an eighth wider than the stroke.
+	capRound
+)
+
+// capNames lists the cap styles by their values.
+var capNames = []string{"butt", "square", "round"}
+
+// LookupCap returns the cap style with the given name.
+func LookupCap(name string) (int, error) {
+	for c, n := range capNames {
+		if n == name {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown line cap %q (available: %s)", name, strings.Join(capNames, ", "))
+}
+
+// SetStroke draws the sticks as anti-aliased strokes of the width with the cap style.
+// The zero width draws them as the faster, but jagged 1 pixel wide outlines.
+func (c *Cloth) SetStroke(width float64, lineCap int) {
+	c.strokeWidth, c.lineCap = width, lineCap
+}
+
+// stroked reports whether the sticks are drawn as strokes. The tension width takes precedence over the stroke.
+func (c *Cloth) stroked() bool {
+	return c.strokeWidth > 0 && c.maxWidth <= 0
+}
+
+// addStroke adds the segment of the stick with its caps to the stroked path.
+func (c *Cloth) addStroke(path *clip.Path, ct *Constraint, alpha float64) {
+	x1, y1 := ct.p1.position(alpha)
+	x2, y2 := ct.p2.position(alpha)
+	half := c.strokeWidth / 2
+	switch c.lineCap {
+	case capSquare:
+		if dist := distance(x2-x1, y2-y1); dist > 0 {
+			dx, dy := (x2-x1)/dist*half, (y2-y1)/dist*half
+			x1, y1, x2, y2 = x1-dx, y1-dy, x2+dx, y2+dy
+		}
+	case capRound:
+		// Gio has no round caps, but stroking a tiny circle fills a disc around the particle, rounding off the end.
+		// The disc radius is the half width plus the radius of the circle, so it only approximates a true round cap.
+		addDot(path, float32(x1), float32(y1), float32(half/8))
+		addDot(path, float32(x2), float32(y2), float32(half/8))
+	}
+	path.MoveTo(f32.Pt(float32(x1), float32(y1)))
+	path.LineTo(f32.Pt(float32(x2), float32(y2)))
+}
+
+// fillSticks paints the sticks added to the path with the color, stroking the path if the sticks are stroked.
+func (c *Cloth) fillSticks(gtx layout.Context, col color.NRGBA, path *clip.Path) {
+	if c.stroked() {
+		paint.FillShape(gtx.Ops, col, clip.Stroke{Path: path.End(), Width: float32(c.strokeWidth)}.Op())
+		return
+	}
+	paint.FillShape(gtx.Ops, col, clip.Outline{Path: path.End()}.Op())
+}
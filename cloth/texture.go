@@ -0,0 +1,89 @@
+package cloth
+
+import (
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+// LoadTexture loads the PNG or JPEG image from the file at the `path`.
+func LoadTexture(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	return img, err
+}
+
+// SetTexture sets the image mapped across the cloth grid. The nil image turns off the texture mapping.
+func (c *Cloth) SetTexture(img image.Image) {
+	if img == nil {
+		c.texture = nil
+		return
+	}
+	texture := paint.NewImageOp(img)
+	c.texture = &texture
+}
+
+// drawTexture maps the texture across the cells of the cloth, which are split into two triangles each.
+// Every triangle is transformed separately, so the image deforms and tears together with the cloth.
+// It reports whether the cloth has any cells to map the texture on.
+func (c *Cloth) drawTexture(gtx layout.Context, alpha float64) bool {
+	quads := c.quads()
+	if len(quads) == 0 {
+		return false
+	}
+	// The texture is stretched over the whole grid of each sheet as it has been built,
+	// so it stays in place when the edges of the cloth are torn off.
+	size := c.texture.Size()
+	u := float32(size.X) / float32(c.gridCols)
+	v := float32(size.Y) / float32(c.gridRows)
+
+	for _, q := range quads {
+		var pts, uvs [4]f32.Point
+		for i, p := range q {
+			x, y := p.position(alpha)
+			pts[i] = f32.Pt(float32(x), float32(y))
+			uvs[i] = f32.Pt(float32(p.col)*u, float32(p.row)*v)
+		}
+		c.drawTriangle(gtx, [3]f32.Point{pts[0], pts[1], pts[2]}, [3]f32.Point{uvs[0], uvs[1], uvs[2]})
+		c.drawTriangle(gtx, [3]f32.Point{pts[0], pts[2], pts[3]}, [3]f32.Point{uvs[0], uvs[2], uvs[3]})
+	}
+	return true
+}
+
+// drawTriangle paints the `uvs` triangle of the texture into the `pts` triangle.
+func (c *Cloth) drawTriangle(gtx layout.Context, pts, uvs [3]f32.Point) {
+	// The affine transformation mapping the texture triangle to the cloth triangle
+	// is the product of the edge vectors of the cloth triangle and the inverse of the texture edge vectors.
+	e1, e2 := uvs[1].Sub(uvs[0]), uvs[2].Sub(uvs[0])
+	f1, f2 := pts[1].Sub(pts[0]), pts[2].Sub(pts[0])
+	det := e1.X*e2.Y - e2.X*e1.Y
+	if det == 0 {
+		return
+	}
+	sx, hx := (f1.X*e2.Y-f2.X*e1.Y)/det, (f2.X*e1.X-f1.X*e2.X)/det
+	hy, sy := (f1.Y*e2.Y-f2.Y*e1.Y)/det, (f2.Y*e1.X-f1.Y*e2.X)/det
+	ox := pts[0].X - sx*uvs[0].X - hx*uvs[0].Y
+	oy := pts[0].Y - hy*uvs[0].X - sy*uvs[0].Y
+
+	var path clip.Path
+	path.Begin(gtx.Ops)
+	path.MoveTo(pts[0])
+	path.LineTo(pts[1])
+	path.LineTo(pts[2])
+	path.Close()
+	defer clip.Outline{Path: path.End()}.Op().Push(gtx.Ops).Pop()
+	defer op.Affine(f32.NewAffine2D(sx, hx, ox, hy, sy, oy)).Push(gtx.Ops).Pop()
+	c.texture.Add(gtx.Ops)
+	paint.PaintOp{}.Add(gtx.Ops)
+}
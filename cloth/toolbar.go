@@ -0,0 +1,126 @@
+package cloth
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+	"golang.org/x/exp/shiny/materialdesign/icons"
+)
+
+// toolbarInset is the distance of the toolbar from the bottom edge of the widget.
+const toolbarInset = 8
+
+// tearSliderWidth is the width of the tear threshold slider at the end of the toolbar.
+const tearSliderWidth = 120
+
+// inactiveColor is the background of the toolbar buttons which are not selected.
+var inactiveColor = color.NRGBA{R: 0x90, G: 0x90, B: 0x90, A: 0xff}
+
+// toolButton is a button of the toolbar calling its action when clicked,
+// which is highlighted while the state it switches to is active.
+type toolButton struct {
+	icon   *widget.Icon
+	label  string
+	click  widget.Clickable
+	action func(w *ClothWidget)
+	active func(w *ClothWidget) bool
+}
+
+// Toolbar is a row of buttons along the bottom edge of the widget, which selects the tool
+// of the pointer, toggles the wind and the pause and resets the cloth, so all
+// the basic interactions, including undoing an accidental tear, are available without the keyboard.
+// It ends with a slider of the tear threshold, whose rightmost position makes the cloth untearable.
+type Toolbar struct {
+	buttons []*toolButton
+	tear    widget.Float
+}
+
+// newToolbar creates the toolbar with a button for every tool.
+func newToolbar() *Toolbar {
+	t := &Toolbar{}
+	tools := []struct {
+		icon  []byte
+		label string
+	}{
+		toolPush:   {icons.ActionPanTool, "Push"},
+		toolPull:   {icons.ActionTouchApp, "Pull"},
+		toolCut:    {icons.ContentContentCut, "Cut"},
+		toolPin:    {icons.MapsPlace, "Pin"},
+		toolTear:   {icons.ImageBrokenImage, "Tear"},
+		toolThrow:  {icons.AVFiberManualRecord, "Throw"},
+		toolSelect: {icons.ContentSelectAll, "Select"},
+	}
+	for tool, b := range tools {
+		tool := tool
+		t.add(b.icon, b.label,
+			func(w *ClothWidget) { w.setTool(tool) },
+			func(w *ClothWidget) bool { return w.tool == tool })
+	}
+	t.add(icons.ImageWBCloudy, "Wind",
+		func(w *ClothWidget) { w.cloth.ToggleWind() },
+		func(w *ClothWidget) bool { return w.cloth.WindModel().Enabled })
+	t.add(icons.AVPause, "Pause",
+		(*ClothWidget).togglePause,
+		func(w *ClothWidget) bool { return w.paused })
+	t.add(icons.ContentUndo, "Undo", (*ClothWidget).Undo, nil)
+	t.add(icons.ContentRedo, "Redo", (*ClothWidget).Redo, nil)
+	t.add(icons.NavigationRefresh, "Reset", (*ClothWidget).Reset, nil)
+	return t
+}
+
+// add adds a new button to the toolbar. The `active` function is optional.
+func (t *Toolbar) add(data []byte, label string, action func(w *ClothWidget), active func(w *ClothWidget) bool) {
+	icon, err := widget.NewIcon(data)
+	if err != nil {
+		panic(err)
+	}
+	t.buttons = append(t.buttons, &toolButton{icon: icon, label: label, action: action, active: active})
+}
+
+// Layout calls the actions of the clicked buttons and draws the toolbar centered at the bottom of the widget.
+func (t *Toolbar) Layout(gtx layout.Context, w *ClothWidget) {
+	for _, b := range t.buttons {
+		if b.click.Clicked() {
+			b.action(w)
+			w.idle.Wake()
+		}
+	}
+	// The slider follows the threshold changed with the hotkeys, unless it's being dragged.
+	if t.tear.Changed() {
+		w.cloth.SetTearThreshold(float64(t.tear.Value))
+	} else if !t.tear.Dragging() {
+		t.tear.Value = float32(math.Min(w.cloth.TearThreshold(), MaxTearDist+1))
+	}
+
+	macro := op.Record(gtx.Ops)
+	gtx.Constraints.Min = image.Point{}
+	children := make([]layout.FlexChild, len(t.buttons), len(t.buttons)+1)
+	for i, b := range t.buttons {
+		b := b
+		children[i] = layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			btn := material.IconButton(w.Theme, &b.click, b.icon, b.label)
+			btn.Size, btn.Inset = unit.Dp(20), layout.UniformInset(unit.Dp(8))
+			if b.active == nil || !b.active(w) {
+				btn.Background = inactiveColor
+			}
+			return layout.UniformInset(unit.Dp(2)).Layout(gtx, btn.Layout)
+		})
+	}
+	children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+		gtx.Constraints.Min.X = gtx.Dp(tearSliderWidth)
+		gtx.Constraints.Max.X = gtx.Constraints.Min.X
+		return material.Slider(w.Theme, &t.tear, MinTearDist, MaxTearDist+1).Layout(gtx)
+	}))
+	dims := layout.Flex{Alignment: layout.Middle}.Layout(gtx, children...)
+	call := macro.Stop()
+
+	pos := image.Pt((w.size.X-dims.Size.X)/2, w.size.Y-dims.Size.Y-gtx.Dp(toolbarInset))
+	defer op.Offset(pos).Push(gtx.Ops).Pop()
+	call.Add(gtx.Ops)
+}
@@ -0,0 +1,67 @@
+package cloth
+
+import (
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+const (
+	// trailFrames is the number of the frames the particle positions are kept for the trails.
+	trailFrames = 8
+	// trailSpeed is the distance in pixels a particle must move between two frames to leave a trail.
+	trailSpeed = 4
+	// trailWidth is the width of the trail segments.
+	trailWidth = 1.5
+	// trailOpacity is the opacity of the newest trail segments, fading out toward the oldest ones.
+	trailOpacity = 0.5
+)
+
+// SetTrails turns on or off the motion trails left behind by the fast moving particles.
+func (c *Cloth) SetTrails(on bool) {
+	c.trails = on
+	c.trail = nil
+}
+
+// Trails reports whether the motion trails are on.
+func (c *Cloth) Trails() bool {
+	return c.trails
+}
+
+// drawTrails records the drawn particle positions and draws the segments between the positions
+// of the last frames as translucent strokes, fading out with their age, for the particles moving fast.
+func (c *Cloth) drawTrails(gtx layout.Context, alpha float64) {
+	frame := make([]f32.Point, len(c.particles))
+	for i, p := range c.particles {
+		x, y := p.position(alpha)
+		frame[i] = f32.Pt(float32(x), float32(y))
+	}
+	// The positions of the different cloth, e.g. after switching the preset, are not connected.
+	if len(c.trail) > 0 && len(c.trail[0]) != len(frame) {
+		c.trail = nil
+	}
+	c.trail = append(c.trail, frame)
+	if len(c.trail) > trailFrames {
+		c.trail = c.trail[1:]
+	}
+
+	for k := 1; k < len(c.trail); k++ {
+		prev, next := c.trail[k-1], c.trail[k]
+		var path clip.Path
+		path.Begin(gtx.Ops)
+		for i, p := range c.particles {
+			if !p.isActive {
+				continue
+			}
+			if d := next[i].Sub(prev[i]); d.X*d.X+d.Y*d.Y < trailSpeed*trailSpeed {
+				continue
+			}
+			path.MoveTo(prev[i])
+			path.LineTo(next[i])
+		}
+		col := c.color
+		col.A = uint8(float64(col.A) * trailOpacity * float64(k) / float64(len(c.trail)-1))
+		paint.FillShape(gtx.Ops, col, clip.Stroke{Path: path.End(), Width: trailWidth}.Op())
+	}
+}
@@ -0,0 +1,39 @@
+package cloth
+
+import "math"
+
+const (
+	// waterBuoyancy is the fraction of the gravity canceled by the buoyancy underwater.
+	waterBuoyancy = 0.9
+	// waterDrag is the fraction of the velocity lost to the water in every step, on top of the air drag.
+	waterDrag = 0.08
+	// currentStrength is the acceleration of the water current.
+	currentStrength = 120
+	// currentPeriod is the time in seconds of a full swing of the water current.
+	currentPeriod = 6.0
+	// currentWave is the phase shift of the current per pixel of depth, making the cloth sway like a kelp.
+	currentWave = 0.01
+)
+
+// waterAt returns the acceleration of the buoyancy and the current acting on a particle
+// at the {x, y} position and the drag of the water, when the cloth is underwater.
+func (c *Cloth) waterAt(x, y float64) (ax, ay, drag float64) {
+	if !c.underwater {
+		return 0, 0, 0
+	}
+	current := fround(currentStrength * sin(2*math.Pi*c.simTime/currentPeriod+fround(y*currentWave)))
+	ax = current - fround(c.forces.GravityX*waterBuoyancy)
+	ay = fround(-c.forces.GravityY * waterBuoyancy)
+	return ax, ay, waterDrag
+}
+
+// SetUnderwater puts the cloth underwater, where the buoyancy is opposing the gravity,
+// the water slows down the motion and the slowly oscillating current sways the cloth.
+func (c *Cloth) SetUnderwater(on bool) {
+	c.underwater = on
+}
+
+// Underwater reports whether the cloth is underwater.
+func (c *Cloth) Underwater() bool {
+	return c.underwater
+}
@@ -0,0 +1,72 @@
+package cloth
+
+import "math"
+
+const (
+	// windGustPeriod is the time in seconds between two wind gusts.
+	windGustPeriod = 3.0
+	// windNoiseScale is the spatial frequency of the turbulence per pixel.
+	windNoiseScale = 0.01
+	// windNoiseSpeed is the rate the turbulence pattern changes per second.
+	windNoiseSpeed = 0.8
+)
+
+// WindModel describes the wind blowing over the cloth: a directional base wind,
+// periodic gusts reinforcing it and turbulence varying from particle to particle.
+type WindModel struct {
+	Enabled bool
+	// Strength is the acceleration of the base wind.
+	Strength float64
+	// Direction is the angle of the wind in radians, where zero blows to the right.
+	Direction float64
+	// Gusts is the extra strength of the gusts, as a fraction of the base strength.
+	Gusts float64
+	// Turbulence is the strength of the Perlin noise turbulence, as a fraction of the base strength.
+	Turbulence float64
+}
+
+// windAt returns the wind acceleration acting on a particle at the {x, y} position.
+// The wind changes over the simulated time, so it's replayed identically from a snapshot.
+func (c *Cloth) windAt(x, y float64) (wx, wy float64) {
+	m := c.windModel
+	if !m.Enabled || m.Strength == 0 {
+		return 0, 0
+	}
+	strength := c.gustStrength()
+	wx = fround(cos(m.Direction)*strength) + c.turbulenceAt(x, y, 0, 0)
+	wy = fround(sin(m.Direction)*strength) + c.turbulenceAt(x, y, 31.4, 27.1)
+	return wx, wy
+}
+
+// gustStrength returns the strength of the wind reinforced by the gusts at the current simulated time.
+func (c *Cloth) gustStrength() float64 {
+	m := c.windModel
+	gust := math.Max(0, sin(2*math.Pi*c.simTime/windGustPeriod))
+	return m.Strength * (1 + fround(m.Gusts*gust*gust))
+}
+
+// turbulenceAt returns the turbulence of the wind at the {x, y} position. Each component of the wind
+// samples the noise shifted by a different {dx, dy} offset, so they are changing independently.
+func (c *Cloth) turbulenceAt(x, y, dx, dy float64) float64 {
+	m := c.windModel
+	if m.Turbulence <= 0 || c.noise == nil {
+		return 0
+	}
+	nx, ny, nt := fround(x*windNoiseScale), fround(y*windNoiseScale), c.simTime*windNoiseSpeed
+	return fround(c.noise.At(nx+dx, ny+dy, nt) * (m.Turbulence * m.Strength))
+}
+
+// WindModel returns the wind blowing over the cloth.
+func (c *Cloth) WindModel() WindModel {
+	return c.windModel
+}
+
+// SetWindModel changes the wind blowing over the cloth.
+func (c *Cloth) SetWindModel(m WindModel) {
+	c.windModel = m
+}
+
+// ToggleWind turns the wind on or off.
+func (c *Cloth) ToggleWind() {
+	c.windModel.Enabled = !c.windModel.Enabled
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/esimov/gio-cloth/cloth"
+)
+
+// EmbedExample shows how the cloth widget can be embedded into any Gio user interface:
+// the cloth is laid out in a layout.Flex next to a sidebar with other material widgets.
+// Typing into the text field doesn't affect the cloth, since the cloth handles
+// the key events only after it has been clicked.
+type EmbedExample struct {
+	theme *material.Theme
+	cloth *cloth.ClothWidget
+	reset widget.Clickable
+	notes widget.Editor
+	tear  widget.Float
+	scene widget.Enum
+	// warned is the time of the last tension warning of the cloth.
+	warned time.Time
+}
+
+// NewEmbedExample creates the embedding example around the cloth widget.
+func NewEmbedExample(th *material.Theme, w *cloth.ClothWidget) *EmbedExample {
+	e := &EmbedExample{theme: th, cloth: w}
+	e.scene.Value = w.Preset().Name
+	w.OnTension = func(float64) {
+		e.warned = time.Now()
+	}
+	return e
+}
+
+// tearLabel returns the label of the tear threshold slider.
+func (e *EmbedExample) tearLabel() string {
+	if c := e.cloth.Cloth(); c != nil && !math.IsInf(c.TearThreshold(), 1) {
+		return fmt.Sprintf("Tear threshold: %.0f", c.TearThreshold())
+	}
+	return "Tear threshold: untearable"
+}
+
+// Layout lays out the sidebar and the cloth widget filling the remaining space.
+func (e *EmbedExample) Layout(gtx layout.Context) layout.Dimensions {
+	if e.reset.Clicked() {
+		e.cloth.Reset()
+	}
+	if e.scene.Changed() {
+		if p, err := cloth.LookupPreset(e.scene.Value); err == nil {
+			e.cloth.SetPreset(p)
+		}
+	} else {
+		e.scene.Value = e.cloth.Preset().Name
+	}
+	// The slider follows the threshold changed with the hotkeys, unless it's being dragged.
+	if c := e.cloth.Cloth(); c != nil {
+		if e.tear.Changed() {
+			c.SetTearThreshold(float64(e.tear.Value))
+		} else if !e.tear.Dragging() {
+			e.tear.Value = float32(math.Min(c.TearThreshold(), cloth.MaxTearDist+1))
+		}
+	}
+
+	return layout.Flex{}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.UniformInset(unit.Dp(16)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(material.H6(e.theme, "Embedded cloth").Layout),
+					layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+					layout.Rigid(material.Button(e.theme, &e.reset, "Reset").Layout),
+					layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+					layout.Rigid(e.presetMenu),
+					layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+					layout.Rigid(material.Body1(e.theme, e.tearLabel()).Layout),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Min.X = gtx.Dp(unit.Dp(200))
+						gtx.Constraints.Max.X = gtx.Constraints.Min.X
+						return material.Slider(e.theme, &e.tear, cloth.MinTearDist, cloth.MaxTearDist+1).Layout(gtx)
+					}),
+					layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Max.X = gtx.Dp(unit.Dp(200))
+						return material.Editor(e.theme, &e.notes, "Type here...").Layout(gtx)
+					}),
+					layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						if time.Since(e.warned) > time.Second {
+							return layout.Dimensions{}
+						}
+						return material.Body1(e.theme, "The cloth is about to tear!").Layout(gtx)
+					}),
+				)
+			})
+		}),
+		layout.Flexed(1, e.cloth.Layout),
+	)
+}
+
+// presetMenu lays out the radio buttons switching between the scene presets.
+func (e *EmbedExample) presetMenu(gtx layout.Context) layout.Dimensions {
+	children := make([]layout.FlexChild, len(cloth.PresetNames))
+	for i, name := range cloth.PresetNames {
+		children[i] = layout.Rigid(material.RadioButton(e.theme, &e.scene, name, name).Layout)
+	}
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+}
@@ -0,0 +1,24 @@
+//go:build !gamepad || !linux
+
+package main
+
+import (
+	"gioui.org/app"
+
+	"github.com/esimov/gio-cloth/cloth"
+)
+
+// Gamepad is a no-op placeholder used when the application
+// is built without the `gamepad` build tag. On the other platforms
+// than Linux the tag is rejected by gamepad_other.go.
+type Gamepad struct{}
+
+type gamepadState struct{}
+
+func openGamepad() *Gamepad { return nil }
+
+func (g *Gamepad) attach(w *app.Window) {}
+
+func (g *Gamepad) detach(w *app.Window) {}
+
+func (g *Gamepad) apply(c *cloth.Cloth, state *gamepadState) {}
@@ -0,0 +1,157 @@
+//go:build gamepad && linux
+
+package main
+
+import (
+	"encoding/binary"
+	"flag"
+	"log"
+	"math"
+	"os"
+	"sync"
+
+	"gioui.org/app"
+
+	"github.com/esimov/gio-cloth/cloth"
+)
+
+const (
+	jsEventAxis = 0x02
+	jsEventInit = 0x80
+
+	// Axis numbers of the usual (Xbox like) controller layout.
+	axisLeftX     = 0
+	axisLeftY     = 1
+	axisLeftTrig  = 2
+	axisRightX    = 3
+	axisRightY    = 4
+	axisRightTrig = 5
+	axisCount     = 6
+
+	axisDeadZone = 0.15
+	maxWindForce = 800
+)
+
+var gamepadDevice string
+
+func init() {
+	flag.StringVar(&gamepadDevice, "gamepad", "/dev/input/js0", "gamepad device controlling the wind and the gravity")
+}
+
+// Gamepad reads the controller axes through the Linux joystick API.
+// The left stick sets the wind vector, the right stick tilts the gravity
+// and the right trigger increases the wind strength.
+// The gamepad is shared by all the windows, each of them applying its axes to its own cloth.
+type Gamepad struct {
+	mu      sync.Mutex
+	axes    [axisCount]float64
+	serials [axisCount]int // increased by every event of the axis
+	windows map[*app.Window]bool
+}
+
+// gamepadState is the state of the gamepad last applied to the cloth of a window.
+type gamepadState struct {
+	serials [axisCount]int
+}
+
+// moved reports whether any of the axes has changed since the state has been applied.
+func (s *gamepadState) moved(g *Gamepad, axes ...int) bool {
+	for _, axis := range axes {
+		if s.serials[axis] != g.serials[axis] {
+			return true
+		}
+	}
+	return false
+}
+
+// openGamepad opens the gamepad device and starts listening for its events.
+// It returns nil if the device is not available.
+func openGamepad() *Gamepad {
+	f, err := os.Open(gamepadDevice)
+	if err != nil {
+		log.Printf("gamepad: %v", err)
+		return nil
+	}
+	g := &Gamepad{windows: make(map[*app.Window]bool)}
+	// The triggers are reported in the [-1, 1] range, where -1 is the released state.
+	g.axes[axisLeftTrig], g.axes[axisRightTrig] = -1, -1
+
+	go func() {
+		defer f.Close()
+		// The joystick event layout is: time uint32, value int16, type uint8, number uint8.
+		var ev struct {
+			Time   uint32
+			Value  int16
+			Type   uint8
+			Number uint8
+		}
+		for {
+			if err := binary.Read(f, binary.LittleEndian, &ev); err != nil {
+				log.Printf("gamepad: %v", err)
+				return
+			}
+			if ev.Type&^jsEventInit != jsEventAxis || int(ev.Number) >= len(g.axes) {
+				continue
+			}
+			g.mu.Lock()
+			g.axes[ev.Number] = float64(ev.Value) / math.MaxInt16
+			g.serials[ev.Number]++
+			for w := range g.windows {
+				w.Invalidate()
+			}
+			g.mu.Unlock()
+		}
+	}()
+	return g
+}
+
+// attach redraws the window on the gamepad events, so it can apply them.
+func (g *Gamepad) attach(w *app.Window) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	g.windows[w] = true
+	g.mu.Unlock()
+}
+
+// detach stops redrawing the closed window.
+func (g *Gamepad) detach(w *app.Window) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	delete(g.windows, w)
+	g.mu.Unlock()
+}
+
+// apply feeds the gamepad axes which have changed since they have been applied
+// to the cloth the last time into its wind and gravity.
+func (g *Gamepad) apply(c *cloth.Cloth, state *gamepadState) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if state.moved(g, axisLeftX, axisLeftY, axisRightTrig) {
+		strength := maxWindForce * (1 + (g.axes[axisRightTrig]+1)/2)
+		c.SetWind(deadZone(g.axes[axisLeftX])*strength, deadZone(g.axes[axisLeftY])*strength)
+	}
+	// Tilt the gravity towards the right stick direction keeping its magnitude. The centered stick
+	// leaves the gravity alone, so it doesn't override the gravity set with the keyboard.
+	if state.moved(g, axisRightX, axisRightY) {
+		if x, y := deadZone(g.axes[axisRightX]), deadZone(g.axes[axisRightY]); x != 0 || y != 0 {
+			c.SetGravityDirection(x, y)
+		}
+	}
+	state.serials = g.serials
+}
+
+// deadZone ignores the small stick deflections around the center position.
+func deadZone(v float64) float64 {
+	if math.Abs(v) < axisDeadZone {
+		return 0
+	}
+	return v
+}
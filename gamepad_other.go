@@ -0,0 +1,7 @@
+//go:build gamepad && !linux
+
+package main
+
+// The gamepad is only read from the Linux joystick device, so the `gamepad` build tag
+// is refused on the other platforms instead of building silently without the gamepad.
+var _ = gamepadIsOnlySupportedOnLinux
@@ -5,6 +5,7 @@ go 1.19
 require (
 	gioui.org v0.0.0-20230107005120-f8221bb2ab3a
 	github.com/loov/hrtime v1.0.3
+	golang.org/x/exp/shiny v0.0.0-20220827204233-334a2380cb91
 )
 
 require (
@@ -13,7 +14,6 @@ require (
 	github.com/benoitkugler/textlayout v0.3.0 // indirect
 	github.com/go-text/typesetting v0.0.0-20221214153724-0399769901d5 // indirect
 	golang.org/x/exp v0.0.0-20221012211006-4de253d81b95 // indirect
-	golang.org/x/exp/shiny v0.0.0-20220827204233-334a2380cb91 // indirect
 	golang.org/x/image v0.0.0-20220722155232-062f8c9fd539 // indirect
 	golang.org/x/sys v0.0.0-20220825204002-c680a09ffe64 // indirect
 	golang.org/x/text v0.3.7 // indirect
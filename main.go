@@ -1,218 +1,223 @@
-package main
-
-import (
-	"flag"
-	"image"
-	"image/color"
-	"log"
-	"os"
-	"runtime/pprof"
-	"time"
-
-	"gioui.org/app"
-	"gioui.org/font/gofont"
-	"gioui.org/io/key"
-	"gioui.org/io/pointer"
-	"gioui.org/io/system"
-	"gioui.org/layout"
-	"gioui.org/op"
-	"gioui.org/op/paint"
-	"gioui.org/unit"
-	"gioui.org/widget/material"
-
-	"github.com/loov/hrtime"
-)
-
-const (
-	windowWidth  = 940
-	windowHeight = 580
-)
-
-var (
-	cpuprofile string
-	debugFrame bool
-	f          *os.File
-	err        error
-)
-
-func main() {
-	flag.StringVar(&cpuprofile, "debug-cpuprofile", "", "write CPU profile to this file")
-	flag.BoolVar(&debugFrame, "debug-frame", false, "debug the Gio frame rates")
-	flag.Parse()
-
-	if cpuprofile != "" {
-		f, err = os.Create(cpuprofile)
-		if err != nil {
-			log.Fatal(err)
-		}
-	}
-
-	go func() {
-		w := app.NewWindow(
-			app.Title("Gio - Tearable Cloth"),
-			app.Size(unit.Dp(windowWidth), unit.Dp(windowHeight)),
-		)
-		if err := loop(w); err != nil {
-			log.Fatal(err)
-		}
-		os.Exit(0)
-	}()
-	app.Main()
-}
-
-func loop(w *app.Window) error {
-	var (
-		ops       op.Ops
-		initTime  time.Time
-		deltaTime time.Duration
-		scrollY   unit.Dp
-	)
-	if cpuprofile != "" {
-		defer pprof.StopCPUProfile()
-	}
-
-	th := material.NewTheme(gofont.Collection())
-
-	col := color.NRGBA{R: 0x9a, G: 0x9a, B: 0x9a, A: 0xff}
-	mouse := &Mouse{maxScrollY: unit.Dp(200)}
-	isDragging := false
-
-	var clothW int = windowWidth * 1.3
-	var clothH int = windowHeight * 0.4
-	cloth := NewCloth(clothW, clothH, 8, 0.99, col)
-
-	for {
-		select {
-		case e := <-w.Events():
-			switch e := e.(type) {
-			case system.DestroyEvent:
-				return e.Err
-			case system.FrameEvent:
-				start := hrtime.Now()
-				if cpuprofile != "" {
-					pprof.StartCPUProfile(f)
-				}
-
-				gtx := layout.NewContext(&ops, e)
-				if !cloth.isInitialized {
-					width := gtx.Constraints.Max.X
-					height := gtx.Constraints.Max.Y
-
-					startX := width/2 - clothW/2
-					startY := int(float64(height) * 0.2)
-					cloth.Init(startX, startY)
-				}
-
-				pointer.InputOp{
-					Tag:   w,
-					Types: pointer.Scroll | pointer.Move | pointer.Press | pointer.Drag | pointer.Release | pointer.Type(pointer.ButtonPrimary) | pointer.Type(pointer.ButtonSecondary),
-					ScrollBounds: image.Rectangle{
-						Min: image.Point{
-							X: 0,
-							Y: -30,
-						},
-						Max: image.Point{
-							X: 0,
-							Y: 30,
-						},
-					},
-				}.Add(gtx.Ops)
-
-				key.InputOp{
-					Tag:  w,
-					Keys: key.NameEscape + "|" + key.NameCtrl + "|" + key.NameAlt + "|" + key.NameSpace,
-				}.Add(gtx.Ops)
-
-				if mouse.getLeftButton() {
-					deltaTime = time.Now().Sub(initTime)
-					mouse.increaseForce(deltaTime.Seconds())
-				}
-
-				for _, ev := range gtx.Queue.Events(w) {
-					if e, ok := ev.(key.Event); ok {
-						if e.State == key.Press {
-							if e.Name == key.NameSpace {
-								width := gtx.Constraints.Max.X
-								height := gtx.Constraints.Max.Y
-
-								startX := width/2 - clothW/2
-								startY := int(float64(height) * 0.2)
-								cloth.Reset(startX, startY)
-							}
-						}
-						if e.Name == key.NameEscape {
-							w.Perform(system.ActionClose)
-						}
-					}
-
-					switch ev := ev.(type) {
-					case pointer.Event:
-						switch ev.Type {
-						case pointer.Scroll:
-							scrollY += unit.Dp(ev.Scroll.Y)
-							if scrollY < 0 {
-								scrollY = 0
-							} else if scrollY > mouse.maxScrollY {
-								scrollY = mouse.maxScrollY
-							}
-							mouse.setScrollY(scrollY)
-						case pointer.Move:
-							pos := mouse.getCurrentPosition(ev)
-							mouse.updatePosition(float64(pos.X), float64(pos.Y))
-						case pointer.Press:
-							if ev.Modifiers == key.ModCtrl {
-								mouse.setCtrlDown(true)
-							}
-							mouse.setLeftButton()
-							initTime = time.Now()
-						case pointer.Release:
-							isDragging = false
-
-							mouse.resetForce()
-							mouse.releaseLeftButton()
-							mouse.releaseRightButton()
-							mouse.setDragging(isDragging)
-							mouse.setCtrlDown(false)
-						case pointer.Drag:
-							isDragging = true
-						}
-						switch ev.Buttons {
-						case pointer.ButtonPrimary:
-							mouse.setLeftButton()
-							pos := mouse.getCurrentPosition(ev)
-							mouse.updatePosition(float64(pos.X), float64(pos.Y))
-							mouse.setDragging(isDragging)
-						case pointer.ButtonSecondary:
-							mouse.setRightButton()
-							pos := mouse.getCurrentPosition(ev)
-							mouse.updatePosition(float64(pos.X), float64(pos.Y))
-						}
-					}
-				}
-				fillBackground(gtx, color.NRGBA{R: 0xf2, G: 0xf2, B: 0xf2, A: 0xff})
-
-				cloth.Update(gtx, mouse, 0.015)
-
-				if debugFrame {
-					layout.Stack{}.Layout(gtx,
-						layout.Stacked(func(gtx layout.Context) layout.Dimensions {
-							op.Offset(image.Pt(10, 10)).Add(gtx.Ops)
-							return layout.E.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-								m := material.Label(th, unit.Sp(15), hrtime.Since(start).String())
-								m.Color = color.NRGBA{R: 127, G: 0, B: 0, A: 255}
-								return m.Layout(gtx)
-							})
-						}))
-				}
-
-				op.InvalidateOp{}.Add(gtx.Ops)
-				e.Frame(gtx.Ops)
-			}
-		}
-	}
-}
-
-func fillBackground(gtx layout.Context, col color.NRGBA) {
-	paint.ColorOp{Color: col}.Add(gtx.Ops)
-	paint.PaintOp{}.Add(gtx.Ops)
-}
+package main
+
+import (
+	"flag"
+	"log"
+	"math"
+	"os"
+	"runtime/pprof"
+	"sync"
+
+	"gioui.org/app"
+	"gioui.org/font/gofont"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+
+	"github.com/esimov/gio-cloth/cloth"
+)
+
+const (
+	windowWidth  = 940
+	windowHeight = 580
+)
+
+var (
+	cpuprofile    string
+	embedDemo     bool
+	config        = cloth.DefaultConfig()
+	windDir       float64
+	solver        string
+	model         string
+	weakening     string
+	renderMode    string
+	strainShading bool
+	tileBg        bool
+	theme         *material.Theme
+	gamepad       *Gamepad
+	tilt          *Tilt
+	f             *os.File
+	err           error
+
+	windows sync.WaitGroup
+	shaping sync.Mutex
+)
+
+func main() {
+	flag.StringVar(&cpuprofile, "debug-cpuprofile", "", "write CPU profile to this file")
+	flag.BoolVar(&config.DebugFrame, "debug-frame", config.DebugFrame, "debug the Gio frame rates")
+	flag.BoolVar(&config.DebugSolver, "debug-solver", config.DebugSolver, "show the energy and the stability diagnostics of the solver")
+	flag.BoolVar(&config.DebugStrain, "debug-strain", config.DebugStrain, "color every stick by its length error with a legend, regardless of the render mode, toggled with the F2 key")
+	flag.BoolVar(&config.DebugWinding, "debug-winding", config.DebugWinding, "mark the winding of the cloth cells, highlighting the ones flipped by folding, toggled with the F3 key")
+	flag.IntVar(&config.UndoDepth, "undo-depth", config.UndoDepth, "maximum number of undoable edits")
+	flag.IntVar(&config.Snapshots, "snapshots", config.Snapshots, "number of cloth snapshots kept in the history")
+	flag.DurationVar(&config.SnapshotInterval, "snapshot-interval", config.SnapshotInterval, "interval between automatic snapshots (0 to disable)")
+	flag.IntVar(&config.RenderFPS, "render-fps", config.RenderFPS, "limit the rendering rate independently of the physics (0 to render every frame)")
+	flag.IntVar(&config.RenderFPS, "max-fps", config.RenderFPS, "alias of -render-fps")
+	flag.Float64Var(&config.PhysicsHz, "physics-hz", config.PhysicsHz, "run the physics at a fixed rate of steps per second, independently of the refresh rate (0 to step once per frame using the measured frame time)")
+	flag.DurationVar(&config.IdleAfter, "idle-after", config.IdleAfter, "stop the physics after the cloth has settled for this long (0 to disable)")
+	flag.DurationVar(&config.FrameBudget, "frame-budget", config.FrameBudget, "adapt the physics sub-steps and solver iterations to this frame time budget (0 to disable)")
+	flag.IntVar(&config.MinSubSteps, "min-substeps", config.MinSubSteps, "minimum number of physics sub-steps per step")
+	flag.IntVar(&config.MaxSubSteps, "max-substeps", config.MaxSubSteps, "maximum number of physics sub-steps per step")
+	flag.IntVar(&config.MinIterations, "min-iterations", config.MinIterations, "minimum number of constraint solver iterations")
+	flag.IntVar(&config.MaxIterations, "max-iterations", config.MaxIterations, "maximum number of constraint solver iterations")
+	flag.BoolVar(&embedDemo, "embed-example", false, "show the cloth embedded as a widget next to other widgets")
+	flag.Float64Var(&config.DropY, "drop-y", config.DropY, "initial vertical position of the cloth as a fraction of the height (up to 1) or in pixels")
+	flag.Float64Var(&config.Jitter, "init-jitter", config.Jitter, "randomly displace the initial particle positions by this fraction of the spacing")
+	flag.Int64Var(&config.Seed, "seed", config.Seed, "seed of the random number generator")
+	flag.Float64Var(&config.TensionWarning, "tension-warning", config.TensionWarning, "flash the sticks stretched over this fraction of the tear distance (0 to disable)")
+	flag.Float64Var(&config.Gravity, "gravity", config.Gravity, "vertical gravity acceleration (negative values pull the cloth upward)")
+	flag.Float64Var(&config.Wind.Strength, "wind", config.Wind.Strength, "strength of the wind toggled with the W key")
+	flag.Float64Var(&windDir, "wind-dir", 0, "direction of the wind in degrees (0 blows to the right, 90 downward)")
+	flag.Float64Var(&config.Wind.Gusts, "wind-gusts", config.Wind.Gusts, "extra strength of the periodic wind gusts as a fraction of the wind strength")
+	flag.Float64Var(&config.Wind.Turbulence, "turbulence", config.Wind.Turbulence, "strength of the wind turbulence as a fraction of the wind strength")
+	flag.BoolVar(&config.SelfCollision, "self-collision", config.SelfCollision, "keep the layers of the folded cloth from passing through each other")
+	flag.IntVar(&config.SolverIterations, "solver-iterations", config.SolverIterations, "number of constraint solver iterations per step (0 to start from the minimum iterations)")
+	flag.StringVar(&solver, "solver", "pbd", "constraint solver: pbd (position-based relaxation) or xpbd (compliance-based)")
+	flag.Float64Var(&config.Compliance, "compliance", config.Compliance, "compliance (inverse stiffness) of the sticks with the xpbd solver")
+	flag.Float64Var(&config.HemMass, "hem-mass", config.HemMass, "mass of the particles in the bottom row of the cloth relative to the others")
+	flag.Float64Var(&config.DragX, "drag-x", config.DragX, "fraction of the horizontal velocity lost to the air drag in every step")
+	flag.Float64Var(&config.DragY, "drag-y", config.DragY, "fraction of the vertical velocity lost to the air drag in every step")
+	flag.BoolVar(&config.Shear, "shear", config.Shear, "add diagonal shear sticks for a stiffer fabric")
+	flag.BoolVar(&config.Bend, "bend", config.Bend, "add second neighbour bending sticks for a stiffer fabric")
+	flag.Float64Var(&config.TearThreshold, "tear-threshold", config.TearThreshold, "stick length over which the dragged cloth tears, between 10 and 500 (values over 500 or inf disable the tearing)")
+	flag.BoolVar(&config.Plastic, "plastic", config.Plastic, "stretch the sticks permanently before tearing them, like a knitwear")
+	flag.Float64Var(&config.Warp.Stiffness, "warp-stiffness", config.Warp.Stiffness, "stiffness of the vertical sticks relative to the default")
+	flag.Float64Var(&config.Weft.Stiffness, "weft-stiffness", config.Weft.Stiffness, "stiffness of the horizontal sticks relative to the default")
+	flag.Float64Var(&config.Warp.Tear, "warp-tear", config.Warp.Tear, "tear threshold of the vertical sticks relative to the tear threshold")
+	flag.Float64Var(&config.Weft.Tear, "weft-tear", config.Weft.Tear, "tear threshold of the horizontal sticks relative to the tear threshold")
+	flag.Float64Var(&config.Floor.Friction, "floor-friction", config.Floor.Friction, "fraction of the horizontal velocity lost by the particles hitting the floor")
+	flag.Float64Var(&config.Floor.Restitution, "floor-restitution", config.Floor.Restitution, "fraction of the vertical velocity kept by the particles bouncing off the floor")
+	flag.BoolVar(&config.Walls, "walls", config.Walls, "keep the cloth inside the window edges (false lets it swing off-screen)")
+	flag.BoolVar(&config.Deterministic, "deterministic", config.Deterministic, "disable the frame time dependent adaptations, so the seed and the inputs reproduce the same run")
+	flag.BoolVar(&config.Underwater, "underwater", config.Underwater, "start the cloth underwater, swaying in the current like a kelp")
+	flag.Float64Var(&config.FieldRadius, "field-radius", config.FieldRadius, "radius of the charged field around the cursor toggled with the E key")
+	flag.Float64Var(&config.FieldStrength, "field-strength", config.FieldStrength, "strength of the charged field around the cursor")
+	flag.BoolVar(&config.ExplosionTear, "explosion-tear", config.ExplosionTear, "tear the sticks overstretched by the double click explosion")
+	flag.StringVar(&weakening, "weakening", "none", "weaken the sticks: none, bottom (toward the bottom of the cloth) or noise (at random weak spots)")
+	flag.Float64Var(&config.WeakeningAmount, "weakening-amount", config.WeakeningAmount, "stiffness and tear threshold reduction of the weakest sticks")
+	flag.BoolVar(&config.Sleep, "sleep", config.Sleep, "stop integrating the resting particles until they get disturbed")
+	flag.StringVar(&model, "model", "verlet", "cloth model: verlet (position-based constraints) or spring (mass-spring-damper forces)")
+	flag.Float64Var(&config.SpringK, "spring-k", config.SpringK, "stiffness of the springs with the spring model")
+	flag.Float64Var(&config.SpringDamping, "spring-damping", config.SpringDamping, "damping of the springs with the spring model")
+	flag.Float64Var(&config.Friction, "friction", config.Friction, "fraction of the tangential velocity lost by the particles sliding over the obstacles and the sliding pins")
+	flag.BoolVar(&config.SlidingPins, "sliding-pins", config.SlidingPins, "let the pinned particles slide horizontally along the top, like a curtain on a rod")
+	flag.BoolVar(&config.Fill, "fill", config.Fill, "fill the cells of the cloth with a shaded color instead of drawing its sticks")
+	flag.Float64Var(&config.StrainLimit, "strain-limit", config.StrainLimit, "largest stretch of the sticks relative to their length, e.g. 1.1 (0 to disable)")
+	flag.Func("obstacles", "static obstacles, e.g. \"circle:x,y,r;box:x0,y0,x1,y1;line:x0,y0,x1,y1\"", func(s string) (err error) {
+		config.Obstacles, err = cloth.ParseObstacles(s)
+		return err
+	})
+	flag.BoolVar(&strainShading, "strain-shading", false, "alias of -render-mode=strain, coloring the sticks by their strain, from the slack to the tearing ones")
+	flag.Func("strain-colors", "comma separated hex colors of the strain shading gradient (default \"#3060e0,#e03020\")", func(s string) (err error) {
+		config.Gradient, err = cloth.ParseGradient(s)
+		return err
+	})
+	flag.BoolVar(&config.Dark, "dark", config.Dark, "start with the dark color scheme, toggled with the O key")
+	flag.Func("palette", "color palette: classic, neon, pastel or monochrome, cycled with the H key (default \"classic\")", func(s string) (err error) {
+		config.Palette, err = cloth.LookupPalette(s)
+		return err
+	})
+	flag.StringVar(&renderMode, "render-mode", "plain", "coloring of the sticks: plain, strain (by their strain), rainbow (cycling hues) or glow (soft neon glow), switched with the K key")
+	flag.BoolVar(&config.Dots, "dots", config.Dots, "draw a dot at every particle, highlighting the pinned ones, toggled with the J key")
+	flag.BoolVar(&config.Depth, "3d", config.Depth, "simulate the cloth in 3D, blown into the depth by the wind, and draw it in perspective")
+	flag.BoolVar(&config.Bursts, "burst", config.Bursts, "burst out short-lived sparks from the tears")
+	flag.BoolVar(&config.Fraying, "fray", config.Fraying, "leave short threads dangling from the ends of the torn sticks for a while")
+	flag.BoolVar(&config.Shadow, "shadow", config.Shadow, "cast a soft shadow of the cloth onto the floor")
+	flag.BoolVar(&config.Trails, "trails", config.Trails, "leave fading motion trails behind the fast moving particles, toggled with the Y key")
+	flag.BoolVar(&config.TensionWidth, "tension-width", config.TensionWidth, "draw the stretched sticks thinner and the slack ones thicker")
+	flag.Float64Var(&config.MinLineWidth, "min-line-width", config.MinLineWidth, "line width of the most stretched sticks with the tension width")
+	flag.Float64Var(&config.MaxLineWidth, "max-line-width", config.MaxLineWidth, "line width of the slack sticks with the tension width")
+	flag.Float64Var(&config.StrokeWidth, "stroke-width", config.StrokeWidth, "width of the anti-aliased strokes of the sticks, which are slower to draw than the default jagged 1 pixel outlines (0)")
+	flag.Func("line-cap", "cap style of the stroked sticks: butt, square or round, which is approximated by a slightly wider disc (default \"butt\")", func(s string) (err error) {
+		config.LineCap, err = cloth.LookupCap(s)
+		return err
+	})
+	flag.Func("background", "background: solid or gradient in the palette colors, a hex color, comma separated hex colors of a vertical gradient, or a PNG or JPEG image, switched with the Q key (default \"solid\")", func(s string) (err error) {
+		config.Background, err = cloth.ParseBackground(s)
+		return err
+	})
+	flag.BoolVar(&tileBg, "background-tile", false, "tile the background image instead of stretching it")
+	flag.Func("texture", "PNG or JPEG image mapped across the cloth", func(s string) (err error) {
+		config.Texture, err = cloth.LoadTexture(s)
+		return err
+	})
+	flag.Func("preset", "scene preset: cloth, flag, net, trampoline, balloon, blob, rope or sheets (default \"cloth\")", func(s string) (err error) {
+		config.Preset, err = cloth.LookupPreset(s)
+		return err
+	})
+	flag.Func("mode", "alias of -preset", func(s string) (err error) {
+		config.Preset, err = cloth.LookupPreset(s)
+		return err
+	})
+	flag.Parse()
+
+	if solver != "pbd" && solver != "xpbd" {
+		log.Fatalf("unknown solver: %q", solver)
+	}
+	if model != "verlet" && model != "spring" {
+		log.Fatalf("unknown model: %q", model)
+	}
+	if config.Weakening, err = cloth.LookupFalloff(weakening); err != nil {
+		log.Fatal(err)
+	}
+	if strainShading {
+		if renderMode != "plain" && renderMode != "strain" {
+			log.Fatalf("-strain-shading is an alias of -render-mode=strain, it can't be combined with -render-mode=%s", renderMode)
+		}
+		renderMode = "strain"
+	}
+	if config.RenderMode, err = cloth.LookupRenderMode(renderMode); err != nil {
+		log.Fatal(err)
+	}
+	if config.MinLineWidth < 0 || config.MaxLineWidth < config.MinLineWidth {
+		log.Fatalf("invalid line widths: %g, %g", config.MinLineWidth, config.MaxLineWidth)
+	}
+	config.XPBD = solver == "xpbd"
+	config.Springs = model == "spring"
+	config.Wind.Direction = windDir * math.Pi / 180
+	config.Background.Tile = tileBg
+
+	if cpuprofile != "" {
+		f, err = os.Create(cpuprofile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		pprof.StartCPUProfile(f)
+	}
+
+	// The theme and the input devices are created once and shared by all the windows.
+	theme = material.NewTheme(gofont.Collection())
+	gamepad = openGamepad()
+	tilt = openTilt()
+	newWindow()
+	go func() {
+		// Exit only after the last window has been closed.
+		windows.Wait()
+		quit()
+	}()
+	app.Main()
+}
+
+// newWindow opens a new window running its own independent simulation.
+func newWindow() {
+	windows.Add(1)
+	go func() {
+		defer windows.Done()
+
+		w := app.NewWindow(
+			app.Title("Gio - Tearable Cloth"),
+			app.Size(unit.Dp(windowWidth), unit.Dp(windowHeight)),
+		)
+		if err := NewSimulation(w, theme, gamepad, tilt).Run(); err != nil {
+			log.Fatal(err)
+		}
+	}()
+}
+
+// quit stops the CPU profiling and exits the application, closing all the windows.
+func quit() {
+	if cpuprofile != "" {
+		pprof.StopCPUProfile()
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"gioui.org/app"
+	"gioui.org/io/key"
+	"gioui.org/io/system"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/widget/material"
+
+	"github.com/esimov/gio-cloth/cloth"
+)
+
+// Simulation is a self-contained cloth simulation running in its own window,
+// having its own cloth, parameters and event loop.
+type Simulation struct {
+	window   *app.Window
+	theme    *material.Theme
+	widget   *cloth.ClothWidget
+	example  *EmbedExample
+	gamepad  *Gamepad
+	padState gamepadState
+	tilt     *Tilt
+	tiltSeen tiltState
+}
+
+// NewSimulation creates a new simulation for the window. The theme, the gamepad and the tilt are shared
+// between the windows. Every window gets its own copy of the theme though, since its palette
+// follows the color scheme of the window, while the text shaper and the icons are still shared.
+func NewSimulation(w *app.Window, th *material.Theme, gamepad *Gamepad, tilt *Tilt) *Simulation {
+	own := *th
+	th = &own
+
+	s := &Simulation{
+		window:  w,
+		theme:   th,
+		widget:  cloth.NewClothWidget(th, config),
+		gamepad: gamepad,
+		tilt:    tilt,
+	}
+	if embedDemo {
+		s.example = NewEmbedExample(th, s.widget)
+	} else {
+		// The cloth is the only widget in the window, so it should get the keys from the start.
+		s.widget.Focus()
+	}
+	return s
+}
+
+// Run runs the window event loop until the window is closed.
+func (s *Simulation) Run() error {
+	var ops op.Ops
+
+	s.gamepad.attach(s.window)
+	defer s.gamepad.detach(s.window)
+	s.tilt.attach(s.window)
+	defer s.tilt.detach(s.window)
+	for e := range s.window.Events() {
+		switch e := e.(type) {
+		case system.DestroyEvent:
+			return e.Err
+		case system.FrameEvent:
+			gtx := layout.NewContext(&ops, e)
+			// The text shaper of the shared theme can't be used concurrently, so the windows are laid out one at a time.
+			shaping.Lock()
+			s.frame(gtx)
+			shaping.Unlock()
+			e.Frame(gtx.Ops)
+		}
+	}
+	return nil
+}
+
+// frame handles the window level key events and lays out the cloth filling the whole window,
+// or the embedding example if it has been requested.
+func (s *Simulation) frame(gtx layout.Context) {
+	keys := key.Set(key.NameEscape + "|N|Short-Q")
+	if s.example != nil {
+		// The plain keys would fall through to the window while typing into the example widgets.
+		keys = "Short-N|Short-Q"
+	}
+	key.InputOp{
+		Tag:  s.window,
+		Keys: keys,
+	}.Add(gtx.Ops)
+
+	for _, ev := range gtx.Queue.Events(s.window) {
+		if e, ok := ev.(key.Event); ok {
+			s.handleKey(e)
+		}
+	}
+
+	if c := s.widget.Cloth(); c != nil {
+		s.gamepad.apply(c, &s.padState)
+		s.tilt.apply(c, &s.tiltSeen)
+	}
+	if s.example != nil {
+		s.example.Layout(gtx)
+	} else {
+		s.widget.Layout(gtx)
+	}
+}
+
+// handleKey handles the window level key events.
+func (s *Simulation) handleKey(e key.Event) {
+	if e.State == key.Press {
+		switch {
+		case e.Name == "Q" && e.Modifiers.Contain(key.ModShortcut):
+			quit()
+		case e.Name == "N":
+			newWindow()
+		}
+	}
+	if e.Name == key.NameEscape {
+		s.window.Perform(system.ActionClose)
+	}
+}
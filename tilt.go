@@ -0,0 +1,22 @@
+//go:build !android && !ios
+
+package main
+
+import (
+	"gioui.org/app"
+
+	"github.com/esimov/gio-cloth/cloth"
+)
+
+// Tilt is a no-op placeholder used on the platforms without an accelerometer.
+type Tilt struct{}
+
+type tiltState struct{}
+
+func openTilt() *Tilt { return nil }
+
+func (t *Tilt) attach(w *app.Window) {}
+
+func (t *Tilt) detach(w *app.Window) {}
+
+func (t *Tilt) apply(c *cloth.Cloth, state *tiltState) {}
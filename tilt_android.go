@@ -0,0 +1,63 @@
+package main
+
+/*
+#cgo LDFLAGS: -landroid
+
+#include <android/looper.h>
+#include <android/sensor.h>
+
+static ASensorEventQueue *queue;
+
+static int startAccelerometer(void) {
+	ASensorManager *manager = ASensorManager_getInstance();
+	const ASensor *sensor = ASensorManager_getDefaultSensor(manager, ASENSOR_TYPE_ACCELEROMETER);
+	if (sensor == NULL) {
+		return -1;
+	}
+	ALooper *looper = ALooper_prepare(ALOOPER_PREPARE_ALLOW_NON_CALLBACKS);
+	queue = ASensorManager_createEventQueue(manager, looper, 1, NULL, NULL);
+	if (queue == NULL || ASensorEventQueue_enableSensor(queue, sensor) < 0) {
+		return -1;
+	}
+	// The rate is the interval between the events in microseconds.
+	ASensorEventQueue_setEventRate(queue, sensor, 1000000 / 30);
+	return 0;
+}
+
+// readAccelerometer waits for the accelerometer events and returns the latest one.
+static void readAccelerometer(float *x, float *y) {
+	ASensorEvent event;
+	for (;;) {
+		ALooper_pollOnce(-1, NULL, NULL, NULL);
+		int n = 0;
+		while (ASensorEventQueue_getEvents(queue, &event, 1) > 0) {
+			n++;
+		}
+		if (n > 0) {
+			*x = event.acceleration.x;
+			*y = event.acceleration.y;
+			return;
+		}
+	}
+}
+*/
+import "C"
+
+import "errors"
+
+// startAccelerometer starts the accelerometer events through the native sensor API of the NDK.
+// The events are delivered to the looper of the calling thread.
+func startAccelerometer() error {
+	if C.startAccelerometer() != 0 {
+		return errors.New("no accelerometer")
+	}
+	return nil
+}
+
+// readAccelerometer waits for the next accelerometer reading. Android reports the reaction to the gravity
+// in the device coordinates, where y points to the top of the screen, so it's mirrored horizontally.
+func readAccelerometer() (x, y float64) {
+	var ax, ay C.float
+	C.readAccelerometer(&ax, &ay)
+	return -float64(ax), float64(ay)
+}
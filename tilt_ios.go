@@ -0,0 +1,59 @@
+package main
+
+/*
+#cgo CFLAGS: -x objective-c -fobjc-arc
+#cgo LDFLAGS: -framework Foundation -framework CoreMotion
+
+#import <CoreMotion/CoreMotion.h>
+
+static CMMotionManager *manager;
+
+static int startAccelerometer(void) {
+	manager = [[CMMotionManager alloc] init];
+	if (!manager.accelerometerAvailable) {
+		return -1;
+	}
+	manager.accelerometerUpdateInterval = 1.0 / 30;
+	[manager startAccelerometerUpdates];
+	return 0;
+}
+
+static int readAccelerometer(double *x, double *y) {
+	CMAccelerometerData *data = manager.accelerometerData;
+	if (data == nil) {
+		return -1;
+	}
+	*x = data.acceleration.x;
+	*y = data.acceleration.y;
+	return 0;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"time"
+)
+
+// accelerometerRate is the rate the accelerometer is polled at.
+const accelerometerRate = time.Second / 30
+
+// startAccelerometer starts the accelerometer updates of CoreMotion.
+func startAccelerometer() error {
+	if C.startAccelerometer() != 0 {
+		return errors.New("no accelerometer")
+	}
+	return nil
+}
+
+// readAccelerometer polls the next accelerometer reading. CoreMotion reports the gravity
+// in the device coordinates, where y points to the top of the screen, so it's mirrored vertically.
+func readAccelerometer() (x, y float64) {
+	var ax, ay C.double
+	for {
+		time.Sleep(accelerometerRate)
+		if C.readAccelerometer(&ax, &ay) == 0 {
+			return float64(ax), -float64(ay)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+//go:build android || ios
+
+package main
+
+import (
+	"log"
+	"math"
+	"runtime"
+	"sync"
+
+	"gioui.org/app"
+
+	"github.com/esimov/gio-cloth/cloth"
+)
+
+const (
+	// tiltSmoothing is the weight of a new accelerometer reading, filtering out the shaking of the hand.
+	tiltSmoothing = 0.2
+	// tiltThreshold is the smallest change of the normalized tilt turning the gravity, so the noise
+	// of the sensor doesn't keep waking up the resting cloth.
+	tiltThreshold = 0.02
+)
+
+// Tilt turns the gravity towards the low side of the screen, following the accelerometer of the mobile device.
+// The readings are in the screen coordinates of the portrait orientation, where y points downward.
+// Like the gamepad, the tilt is shared by all the windows, each of them applying it to its own cloth.
+type Tilt struct {
+	mu      sync.Mutex
+	x, y    float64
+	serial  int // increased by every change of the tilt
+	windows map[*app.Window]bool
+}
+
+// tiltState is the state of the tilt last applied to the cloth of a window.
+type tiltState struct {
+	serial int
+}
+
+// openTilt starts reading the accelerometer. It returns nil if the device doesn't have one.
+func openTilt() *Tilt {
+	t := &Tilt{y: 1, windows: make(map[*app.Window]bool)}
+	started := make(chan error)
+	go func() {
+		// The sensor events are delivered to the thread which has started them on Android.
+		runtime.LockOSThread()
+		if err := startAccelerometer(); err != nil {
+			started <- err
+			return
+		}
+		started <- nil
+
+		var x, y float64
+		for {
+			ax, ay := readAccelerometer()
+			x += (ax - x) * tiltSmoothing
+			y += (ay - y) * tiltSmoothing
+			// The tilt is the direction of the gravity, its magnitude is kept by the cloth.
+			dist := math.Hypot(x, y)
+			if dist == 0 {
+				continue
+			}
+			t.mu.Lock()
+			if math.Hypot(x/dist-t.x, y/dist-t.y) > tiltThreshold {
+				t.x, t.y = x/dist, y/dist
+				t.serial++
+				for w := range t.windows {
+					w.Invalidate()
+				}
+			}
+			t.mu.Unlock()
+		}
+	}()
+	if err := <-started; err != nil {
+		log.Printf("tilt: %v", err)
+		return nil
+	}
+	return t
+}
+
+// attach redraws the window on the tilt changes, so it can apply them.
+func (t *Tilt) attach(w *app.Window) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.windows[w] = true
+	t.mu.Unlock()
+}
+
+// detach stops redrawing the closed window.
+func (t *Tilt) detach(w *app.Window) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	delete(t.windows, w)
+	t.mu.Unlock()
+}
+
+// apply turns the gravity of the cloth towards the tilt, if it has changed since it has been applied the last time.
+func (t *Tilt) apply(c *cloth.Cloth, state *tiltState) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if state.serial == t.serial {
+		return
+	}
+	state.serial = t.serial
+	c.SetGravityDirection(t.x, t.y)
+}